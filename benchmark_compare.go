@@ -0,0 +1,109 @@
+package tstat
+
+import (
+	"math"
+
+	"github.com/nickfiggins/tstat/internal/mathutil"
+)
+
+const (
+	// Significance is the p-value below which a change between benchmark runs is considered significant.
+	Significance = 0.05
+	// Confidence is the confidence level used for the interval around the median of benchmark samples.
+	Confidence = 0.95
+)
+
+// BenchmarkComparison compares a single metric of a benchmark between two test runs, similar to benchstat.
+type BenchmarkComparison struct {
+	Package string // Package is the package that the benchmark belongs to.
+	Name    string // Name is the full name of the benchmark, without the GOMAXPROCS suffix.
+	Procs   int    // Procs is the value of GOMAXPROCS the benchmark ran with.
+	Unit    string // Unit is the unit of the metric being compared, e.g. "ns/op".
+
+	Old, New BenchmarkSummary // Old and New summarise the samples from each run.
+
+	// Delta is the percent change in the median from the old run to the new run. It's 0 if the
+	// benchmark wasn't in both runs.
+	Delta float64
+	// P is the p-value of a Mann-Whitney U test between the old and new samples.
+	P float64
+	// Significant is true if P is below Significance, meaning the change is unlikely to be noise.
+	Significant bool
+}
+
+// BenchmarkSummary summarises the samples of a benchmark metric from repeated runs, e.g. with -count=N.
+// If the benchmark wasn't run, there are no samples and all values are 0.
+type BenchmarkSummary struct {
+	Samples    []float64 // Samples are the values from each run of the benchmark.
+	Mean       float64   // Mean is the mean of the samples.
+	Median     float64   // Median is the median of the samples.
+	Low, High  float64   // Low and High are the bounds of the confidence interval for the median.
+	Confidence float64   // Confidence is the confidence level of the interval, which is lower with few samples.
+}
+
+func newBenchmarkSummary(samples []float64) BenchmarkSummary {
+	low, high, conf := mathutil.MedianCI(samples, Confidence)
+	return BenchmarkSummary{
+		Samples:    samples,
+		Mean:       mathutil.Mean(samples),
+		Median:     mathutil.Median(samples),
+		Low:        low,
+		High:       high,
+		Confidence: conf,
+	}
+}
+
+// Benchmarks returns the results of all benchmarks in the run.
+func (tr *TestRun) Benchmarks() []Benchmark {
+	var benchmarks []Benchmark
+	for _, pkg := range tr.pkgs {
+		benchmarks = append(benchmarks, pkg.Benchmarks...)
+	}
+	return benchmarks
+}
+
+// benchmarkKey identifies a single metric of a benchmark across runs.
+type benchmarkKey struct {
+	pkg, name string
+	procs     int
+	unit      string
+}
+
+// CompareBenchmarks compares the benchmarks in two test runs. Results from repeated runs of a benchmark are
+// grouped, and each metric is compared separately. Comparisons are returned in the order the benchmarks
+// and metrics first appear in the old run, followed by any that only appear in the new run.
+func CompareBenchmarks(oldRun, newRun TestRun) []BenchmarkComparison {
+	var keys []benchmarkKey
+	samples := make(map[benchmarkKey]*[2][]float64)
+	for i, run := range []TestRun{oldRun, newRun} {
+		for _, b := range run.Benchmarks() {
+			for _, m := range b.Metrics {
+				key := benchmarkKey{pkg: b.Package, name: b.Name, procs: b.Procs, unit: m.Unit}
+				s, ok := samples[key]
+				if !ok {
+					s = &[2][]float64{}
+					samples[key] = s
+					keys = append(keys, key)
+				}
+				s[i] = append(s[i], m.Value)
+			}
+		}
+	}
+
+	comparisons := make([]BenchmarkComparison, len(keys))
+	for i, key := range keys {
+		s := samples[key]
+		cmp := BenchmarkComparison{
+			Package: key.pkg, Name: key.name, Procs: key.procs, Unit: key.unit,
+			Old: newBenchmarkSummary(s[0]),
+			New: newBenchmarkSummary(s[1]),
+			P:   mathutil.MannWhitneyU(s[0], s[1]),
+		}
+		if len(s[0]) > 0 && len(s[1]) > 0 && cmp.Old.Median != 0 {
+			cmp.Delta = (cmp.New.Median - cmp.Old.Median) / math.Abs(cmp.Old.Median) * 100
+		}
+		cmp.Significant = cmp.P < Significance
+		comparisons[i] = cmp
+	}
+	return comparisons
+}
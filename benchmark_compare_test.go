@@ -0,0 +1,70 @@
+package tstat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareBenchmarks(t *testing.T) {
+	runWith := func(name string, ns ...float64) TestRun {
+		benchmarks := make([]Benchmark, len(ns))
+		for i, v := range ns {
+			benchmarks[i] = Benchmark{
+				Name: name, Package: "pkg", Procs: 8, Iterations: 100,
+				Metrics: []BenchmarkMetric{{Value: v, Unit: UnitNsPerOp}, {Value: 16, Unit: UnitBytesPerOp}},
+			}
+		}
+		return TestRun{pkgs: []PackageRun{{pkgName: "pkg", Benchmarks: benchmarks}}}
+	}
+
+	oldRun := runWith("BenchmarkAdd", 100, 102, 98, 101, 99)
+	newRun := runWith("BenchmarkAdd", 50, 51, 49, 52, 48)
+	newRun.pkgs[0].Benchmarks = append(newRun.pkgs[0].Benchmarks, runWith("BenchmarkSub", 10).pkgs[0].Benchmarks...)
+
+	got := CompareBenchmarks(oldRun, newRun)
+	if !assert.Len(t, got, 4) {
+		return
+	}
+
+	ns := got[0]
+	assert.Equal(t, "BenchmarkAdd", ns.Name)
+	assert.Equal(t, UnitNsPerOp, ns.Unit)
+	assert.Equal(t, 8, ns.Procs)
+	assert.Equal(t, []float64{100, 102, 98, 101, 99}, ns.Old.Samples)
+	assert.InDelta(t, 100, ns.Old.Median, 1e-9)
+	assert.InDelta(t, 50, ns.New.Mean, 1e-9)
+	assert.InDelta(t, 98, ns.Old.Low, 1e-9)
+	assert.InDelta(t, 102, ns.Old.High, 1e-9)
+	assert.InDelta(t, -50, ns.Delta, 1e-9)
+	assert.InDelta(t, 2.0/252, ns.P, 1e-9)
+	assert.True(t, ns.Significant)
+
+	bytes := got[1]
+	assert.Equal(t, UnitBytesPerOp, bytes.Unit)
+	assert.InDelta(t, 0, bytes.Delta, 1e-9)
+	assert.InDelta(t, 1, bytes.P, 1e-9)
+	assert.False(t, bytes.Significant)
+
+	added := got[2]
+	assert.Equal(t, "BenchmarkSub", added.Name)
+	assert.Empty(t, added.Old.Samples)
+	assert.Equal(t, []float64{10}, added.New.Samples)
+	assert.InDelta(t, 0, added.Delta, 1e-9)
+	assert.False(t, added.Significant)
+}
+
+func TestCompareBenchmarks_Fixture(t *testing.T) {
+	run, err := Tests("testdata/bench/bench.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := CompareBenchmarks(run, run)
+	assert.Len(t, got, 8) // two benchmarks, four metrics each
+	for _, cmp := range got {
+		assert.Len(t, cmp.Old.Samples, 2)
+		assert.InDelta(t, 0, cmp.Delta, 1e-9)
+		assert.False(t, cmp.Significant)
+	}
+}
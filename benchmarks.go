@@ -0,0 +1,123 @@
+package tstat
+
+import (
+	"github.com/nickfiggins/tstat/internal/gobench"
+	"github.com/nickfiggins/tstat/internal/gotest"
+)
+
+// Units reported by every benchmark, or when running with -benchmem or b.SetBytes.
+const (
+	UnitNsPerOp     = "ns/op"
+	UnitBytesPerOp  = "B/op"
+	UnitAllocsPerOp = "allocs/op"
+	UnitMBPerSec    = "MB/s"
+)
+
+// Benchmark is the result of a single benchmark run. If a benchmark is run multiple times with
+// the -count flag, each run is a separate Benchmark.
+type Benchmark struct {
+	Name       string            // Name is the full name of the benchmark, without the GOMAXPROCS suffix.
+	Package    string            // Package is the package that the benchmark belongs to.
+	Procs      int               // Procs is the value of GOMAXPROCS the benchmark ran with.
+	Iterations int64             // Iterations is the number of times the benchmark loop ran.
+	Metrics    []BenchmarkMetric // Metrics are the values reported by the benchmark, in the order they were reported.
+}
+
+// BenchmarkMetric is a single value reported by a benchmark, such as ns/op or a custom metric
+// reported with b.ReportMetric.
+type BenchmarkMetric struct {
+	Value float64 // Value is the value reported.
+	Unit  string  // Unit is the unit of the value, e.g. "ns/op".
+}
+
+// Metric returns the value of the metric with the given unit, if the benchmark reported it.
+func (b *Benchmark) Metric(unit string) (float64, bool) {
+	for _, m := range b.Metrics {
+		if m.Unit == unit {
+			return m.Value, true
+		}
+	}
+	return 0, false
+}
+
+// NsPerOp returns the nanoseconds per iteration.
+func (b *Benchmark) NsPerOp() float64 {
+	v, _ := b.Metric(UnitNsPerOp)
+	return v
+}
+
+// BytesPerOp returns the bytes allocated per iteration, which is only reported with -benchmem.
+func (b *Benchmark) BytesPerOp() float64 {
+	v, _ := b.Metric(UnitBytesPerOp)
+	return v
+}
+
+// AllocsPerOp returns the allocations per iteration, which is only reported with -benchmem.
+func (b *Benchmark) AllocsPerOp() float64 {
+	v, _ := b.Metric(UnitAllocsPerOp)
+	return v
+}
+
+// Benchmark returns the results of the benchmark with the given name. There will be more than one result
+// if the benchmark was run with the -count flag, or with different values of -cpu.
+func (pr *PackageRun) Benchmark(name string) []Benchmark {
+	var results []Benchmark
+	for _, b := range pr.Benchmarks {
+		if b.Name == name {
+			results = append(results, b)
+		}
+	}
+	return results
+}
+
+// benchmarkParser collects the benchmark results from a package's output.
+type benchmarkParser struct {
+	pkg string
+	// scanners buffer partial lines for each test, or the package itself for output without a test, since
+	// output from different tests can be written in between.
+	scanners map[string]*gobench.Scanner
+}
+
+func (bp *benchmarkParser) add(e gotest.Event) []Benchmark {
+	if e.Action != gotest.Out {
+		return nil
+	}
+
+	if bp.scanners == nil {
+		bp.scanners = make(map[string]*gobench.Scanner)
+	}
+	scanner, ok := bp.scanners[e.Test]
+	if !ok {
+		scanner = &gobench.Scanner{}
+		bp.scanners[e.Test] = scanner
+	}
+	results := scanner.Add(e.Output)
+	if len(results) == 0 {
+		return nil
+	}
+
+	benchmarks := make([]Benchmark, len(results))
+	for i, res := range results {
+		metrics := make([]BenchmarkMetric, len(res.Metrics))
+		for j, m := range res.Metrics {
+			metrics[j] = BenchmarkMetric{Value: m.Value, Unit: m.Unit}
+		}
+		benchmarks[i] = Benchmark{
+			Name:       res.Name,
+			Package:    bp.pkg,
+			Procs:      res.Procs,
+			Iterations: res.Iterations,
+			Metrics:    metrics,
+		}
+	}
+	return benchmarks
+}
+
+func parseBenchmarks(pkg string, events []gotest.Event) []Benchmark {
+	bp := &benchmarkParser{pkg: pkg}
+	var benchmarks []Benchmark
+	for _, e := range events {
+		benchmarks = append(benchmarks, bp.add(e)...)
+	}
+	return benchmarks
+}
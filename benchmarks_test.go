@@ -0,0 +1,104 @@
+package tstat_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/nickfiggins/tstat"
+	"github.com/stretchr/testify/assert"
+)
+
+const benchPkg = "github.com/nickfiggins/tstat/testdata/bench"
+
+func TestPackageRun_Benchmarks(t *testing.T) {
+	run, err := tstat.Tests("testdata/bench/bench.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkg, ok := run.Package(benchPkg)
+	if !ok {
+		t.Fatalf("package %v not found", benchPkg)
+	}
+
+	want := []tstat.Benchmark{
+		benchmark("BenchmarkSum/size=10", 147.3, 10, 80),
+		benchmark("BenchmarkSum/size=10", 150.3, 10, 80),
+		benchmark("BenchmarkSum/size=1000", 2756, 1000, 8192),
+		benchmark("BenchmarkSum/size=1000", 11502, 1000, 8192),
+	}
+	assert.Equal(t, want, pkg.Benchmarks)
+	assert.Equal(t, want[2:], pkg.Benchmark("BenchmarkSum/size=1000"))
+	assert.Empty(t, pkg.Benchmark("BenchmarkSum"))
+
+	b := pkg.Benchmarks[0]
+	assert.InDelta(t, 147.3, b.NsPerOp(), 0.001)
+	assert.InDelta(t, 80, b.BytesPerOp(), 0.001)
+	assert.InDelta(t, 1, b.AllocsPerOp(), 0.001)
+	_, ok = b.Metric(tstat.UnitMBPerSec)
+	assert.False(t, ok)
+
+	f, err := os.Open("testdata/bench/bench.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	stream := tstat.NewTestStream()
+	if err := stream.Consume(f); err != nil {
+		t.Fatal(err)
+	}
+	streamed := stream.Run()
+	streamedPkg, _ := streamed.Package(benchPkg)
+	assert.Equal(t, want, streamedPkg.Benchmarks)
+}
+
+func TestPackageRun_Benchmarks_Interleaved(t *testing.T) {
+	// a partial result is written by a benchmark, and the package writes a partial result of its own before the
+	// benchmark's line is finished.
+	out := `{"Action":"output","Package":"pkg","Test":"BenchmarkSum","Output":"BenchmarkSum/size=10-4 \t"}
+{"Action":"output","Package":"pkg","Output":"BenchmarkSum/size=100-4 \t"}
+{"Action":"output","Package":"pkg","Test":"BenchmarkSum","Output":"    1000\t 147.3 ns/op\n"}
+{"Action":"output","Package":"pkg","Output":"     100\t 1502 ns/op\n"}
+{"Action":"pass","Package":"pkg"}`
+	run, err := tstat.TestsFromReader(strings.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, ok := run.Package("pkg")
+	if !ok {
+		t.Fatal("package pkg not found")
+	}
+
+	want := []tstat.Benchmark{
+		{
+			Name: "BenchmarkSum/size=10", Package: "pkg", Procs: 4, Iterations: 1000,
+			Metrics: []tstat.BenchmarkMetric{{Value: 147.3, Unit: tstat.UnitNsPerOp}},
+		},
+		{
+			Name: "BenchmarkSum/size=100", Package: "pkg", Procs: 4, Iterations: 100,
+			Metrics: []tstat.BenchmarkMetric{{Value: 1502, Unit: tstat.UnitNsPerOp}},
+		},
+	}
+	assert.Equal(t, want, pkg.Benchmarks)
+
+	stream := tstat.NewTestStream()
+	if err := stream.Consume(strings.NewReader(out)); err != nil {
+		t.Fatal(err)
+	}
+	streamed := stream.Run()
+	streamedPkg, _ := streamed.Package("pkg")
+	assert.Equal(t, want, streamedPkg.Benchmarks)
+}
+
+func benchmark(name string, ns, nums, bytes float64) tstat.Benchmark {
+	return tstat.Benchmark{
+		Name: name, Package: benchPkg, Procs: 4, Iterations: 1000,
+		Metrics: []tstat.BenchmarkMetric{
+			{Value: ns, Unit: tstat.UnitNsPerOp},
+			{Value: nums, Unit: "nums/op"},
+			{Value: bytes, Unit: tstat.UnitBytesPerOp},
+			{Value: 1, Unit: tstat.UnitAllocsPerOp},
+		},
+	}
+}
@@ -1,16 +1,18 @@
 package tstat
 
 import (
+	"slices"
+	"strings"
+
 	"github.com/nickfiggins/tstat/internal/gocover"
 	"github.com/nickfiggins/tstat/internal/gofunc"
 	"github.com/nickfiggins/tstat/internal/mathutil"
-	"golang.org/x/exp/maps"
 )
 
 // Coverage is the coverage statistics parsed from a single test profile.
 type Coverage struct {
 	Percent  float64            // Percent is the total percent of statements covered.
-	Packages []*PackageCoverage // Packages is the coverage of each package.
+	Packages []*PackageCoverage // Packages is the coverage of each package, sorted by import path.
 }
 
 // Package returns the coverage of a single package in the run. It's a convenience method
@@ -25,24 +27,30 @@ func (c *Coverage) Package(name string) (*PackageCoverage, bool) {
 }
 
 func newCoverage(coverPkgs []*gocover.PackageStatements, funcProfile []*gofunc.PackageFunctions) *Coverage {
-	packages := make(map[string]*PackageCoverage)
+	packages := make([]*PackageCoverage, 0, len(coverPkgs))
+	byName := make(map[string]*PackageCoverage, len(coverPkgs))
 	covered, total := int64(0), int64(0)
 	for _, pkg := range coverPkgs {
-		packages[pkg.Package] = newPackageCoverage(pkg)
+		pkgCov := newPackageCoverage(pkg)
+		packages = append(packages, pkgCov)
+		byName[pkg.Package] = pkgCov
 		covered += pkg.CoveredStmts
 		total += pkg.Stmts
 	}
 
 	for _, pkg := range funcProfile {
-		pkgCov, ok := packages[pkg.Package]
+		pkgCov, ok := byName[pkg.Package]
 		if !ok {
 			continue
 		}
 		pkgCov.add(pkg)
 	}
+	slices.SortFunc(packages, func(a, b *PackageCoverage) int {
+		return strings.Compare(a.Name, b.Name)
+	})
 	return &Coverage{
 		Percent:  mathutil.Percent(covered, total),
-		Packages: maps.Values(packages),
+		Packages: packages,
 	}
 }
 
@@ -50,7 +58,7 @@ func newCoverage(coverPkgs []*gocover.PackageStatements, funcProfile []*gofunc.P
 type PackageCoverage struct {
 	Name    string          // Name is the name of the package.
 	Percent float64         // Percent is the percentage of statements covered in the package.
-	Files   []*FileCoverage // Files is the coverage of each file in the package.
+	Files   []*FileCoverage // Files is the coverage of each file in the package, sorted by name.
 }
 
 // File returns the coverage of a file in the package. It's a convenience method
@@ -86,6 +94,9 @@ func newPackageCoverage(stmts *gocover.PackageStatements) *PackageCoverage {
 		}
 		i++
 	}
+	slices.SortFunc(files, func(a, b *FileCoverage) int {
+		return strings.Compare(a.Name, b.Name)
+	})
 
 	return &PackageCoverage{
 		Name:    stmts.Package,
@@ -99,7 +110,7 @@ func (pc *PackageCoverage) add(pkgFn *gofunc.PackageFunctions) {
 		for _, f := range pc.Files {
 			if f.Name == name {
 				f.Functions = toFunctions(file.Functions)
-				return
+				break
 			}
 		}
 	}
@@ -108,7 +119,7 @@ func (pc *PackageCoverage) add(pkgFn *gofunc.PackageFunctions) {
 type FileCoverage struct {
 	Name         string             // Name is the name of the file.
 	Percent      float64            // Percent is the percent of statements covered.
-	Functions    []FunctionCoverage // Functions is the coverage of each function in the file.
+	Functions    []FunctionCoverage // Functions is the coverage of each function in the file, by line.
 	Stmts        int                // Stmts is the total number of statements in the file.
 	CoveredStmts int                // CoveredStmts is the number of statements covered in the file.
 }
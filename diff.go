@@ -0,0 +1,129 @@
+package tstat
+
+import (
+	"sort"
+	"time"
+)
+
+// TestRunDiff is the difference between two runs of the same test suite, such as the base branch and a pull
+// request. Each list is sorted by package and then by the test's full name.
+type TestRunDiff struct {
+	NewFailures []TestChange // NewFailures are tests that failed in head, but not in base. New tests that failed are included.
+	Fixed       []TestChange // Fixed are tests that failed in base and passed in head.
+	Added       []TestChange // Added are tests that are only in head.
+	Removed     []TestChange // Removed are tests that are only in base.
+	Slower      []TestChange // Slower are tests that took significantly longer in head than in base.
+}
+
+// TestChange is a test that changed between two runs. Base or Head is nil if the test wasn't in that run.
+type TestChange struct {
+	Package  string // Package is the package the test belongs to.
+	FullName string // FullName is the full name of the test, including its parents.
+	Base     *Test
+	Head     *Test
+}
+
+// Delta returns how much longer the test took in head than in base, or 0 if it's missing from either run.
+func (c TestChange) Delta() time.Duration {
+	if c.Base == nil || c.Head == nil {
+		return 0
+	}
+	return c.Head.Duration() - c.Base.Duration()
+}
+
+// DiffOpt is a functional option for configuring how test runs are compared.
+type DiffOpt func(*differ)
+
+// WithSlowdownThreshold sets how much longer a test has to take in head to be reported as slower. The test's
+// duration has to grow by more than the given ratio of its base duration, and by at least minDelta, so small
+// variations in fast tests are ignored. By default, tests have to take 50% and 100ms longer.
+func WithSlowdownThreshold(ratio float64, minDelta time.Duration) DiffOpt {
+	return func(d *differ) {
+		d.ratio, d.minDelta = ratio, minDelta
+	}
+}
+
+type differ struct {
+	ratio    float64
+	minDelta time.Duration
+}
+
+// DiffTestRuns compares the tests in base and head, including subtests. Tests are matched by their package
+// and full name, which have to be exactly the same.
+func DiffTestRuns(base, head TestRun, opts ...DiffOpt) TestRunDiff {
+	d := &differ{ratio: 0.5, minDelta: 100 * time.Millisecond}
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	baseTests, headTests := testsByKey(base), testsByKey(head)
+	var diff TestRunDiff
+	for _, headPkg := range head.pkgs {
+		for _, test := range flatten(headPkg.Tests) {
+			change := TestChange{Package: headPkg.pkgName, FullName: test.FullName, Head: test}
+			change.Base = baseTests[testKey{pkg: headPkg.pkgName, name: test.FullName}]
+			d.add(&diff, change)
+		}
+	}
+
+	for _, basePkg := range base.pkgs {
+		for _, test := range flatten(basePkg.Tests) {
+			if _, ok := headTests[testKey{pkg: basePkg.pkgName, name: test.FullName}]; !ok {
+				diff.Removed = append(diff.Removed, TestChange{Package: basePkg.pkgName, FullName: test.FullName, Base: test})
+			}
+		}
+	}
+
+	for _, changes := range [][]TestChange{diff.NewFailures, diff.Fixed, diff.Added, diff.Removed, diff.Slower} {
+		sortChanges(changes)
+	}
+	return diff
+}
+
+// testKey identifies a test by its package and full name.
+type testKey struct {
+	pkg, name string
+}
+
+// testsByKey returns every test in the run, including subtests, by their package and full name.
+func testsByKey(run TestRun) map[testKey]*Test {
+	tests := make(map[testKey]*Test)
+	for _, pkg := range run.pkgs {
+		for _, test := range flatten(pkg.Tests) {
+			tests[testKey{pkg: pkg.pkgName, name: test.FullName}] = test
+		}
+	}
+	return tests
+}
+
+// add adds a test that's in head to the lists it belongs in.
+func (d *differ) add(diff *TestRunDiff, change TestChange) {
+	if change.Base == nil {
+		diff.Added = append(diff.Added, change)
+		if change.Head.Failed() {
+			diff.NewFailures = append(diff.NewFailures, change)
+		}
+		return
+	}
+
+	switch {
+	case change.Head.Failed() && !change.Base.Failed():
+		diff.NewFailures = append(diff.NewFailures, change)
+	case change.Base.Failed() && change.Head.Status() == StatusPassed:
+		diff.Fixed = append(diff.Fixed, change)
+	}
+
+	base := change.Base.Duration()
+	if delta := change.Delta(); delta >= d.minDelta && float64(delta) > float64(base)*d.ratio {
+		diff.Slower = append(diff.Slower, change)
+	}
+}
+
+func sortChanges(changes []TestChange) {
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Package != changes[j].Package {
+			return changes[i].Package < changes[j].Package
+		}
+		return changes[i].FullName < changes[j].FullName
+	})
+}
@@ -0,0 +1,116 @@
+package tstat_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nickfiggins/tstat"
+	"github.com/stretchr/testify/assert"
+)
+
+func changeNames(changes []tstat.TestChange) []string {
+	names := []string{}
+	for _, c := range changes {
+		names = append(names, c.Package+" "+c.FullName)
+	}
+	return names
+}
+
+func TestDiffTestRuns(t *testing.T) {
+	base := `{"Time":"2023-07-02T10:00:00Z","Action":"start","Package":"a"}
+{"Time":"2023-07-02T10:00:00Z","Action":"run","Package":"a","Test":"TestFixed"}
+{"Time":"2023-07-02T10:00:01Z","Action":"fail","Package":"a","Test":"TestFixed","Elapsed":1}
+{"Time":"2023-07-02T10:00:01Z","Action":"run","Package":"a","Test":"TestBreaks"}
+{"Time":"2023-07-02T10:00:01Z","Action":"run","Package":"a","Test":"TestBreaks/sub"}
+{"Time":"2023-07-02T10:00:01.1Z","Action":"pass","Package":"a","Test":"TestBreaks/sub","Elapsed":0.1}
+{"Time":"2023-07-02T10:00:01.1Z","Action":"pass","Package":"a","Test":"TestBreaks","Elapsed":0.1}
+{"Time":"2023-07-02T10:00:01.1Z","Action":"run","Package":"a","Test":"TestRemoved"}
+{"Time":"2023-07-02T10:00:01.2Z","Action":"pass","Package":"a","Test":"TestRemoved","Elapsed":0.1}
+{"Time":"2023-07-02T10:00:01.2Z","Action":"run","Package":"a","Test":"TestSlow"}
+{"Time":"2023-07-02T10:00:01.4Z","Action":"pass","Package":"a","Test":"TestSlow","Elapsed":0.2}
+{"Time":"2023-07-02T10:00:01.4Z","Action":"run","Package":"a","Test":"TestNoisy"}
+{"Time":"2023-07-02T10:00:01.401Z","Action":"pass","Package":"a","Test":"TestNoisy","Elapsed":0.001}
+{"Time":"2023-07-02T10:00:02Z","Action":"fail","Package":"a","Elapsed":2}
+{"Time":"2023-07-02T10:00:00Z","Action":"start","Package":"gone"}
+{"Time":"2023-07-02T10:00:00Z","Action":"run","Package":"gone","Test":"TestGone"}
+{"Time":"2023-07-02T10:00:01Z","Action":"pass","Package":"gone","Test":"TestGone","Elapsed":1}
+{"Time":"2023-07-02T10:00:01Z","Action":"pass","Package":"gone","Elapsed":1}`
+	head := `{"Time":"2023-07-02T11:00:00Z","Action":"start","Package":"a"}
+{"Time":"2023-07-02T11:00:00Z","Action":"run","Package":"a","Test":"TestFixed"}
+{"Time":"2023-07-02T11:00:01Z","Action":"pass","Package":"a","Test":"TestFixed","Elapsed":1}
+{"Time":"2023-07-02T11:00:01Z","Action":"run","Package":"a","Test":"TestBreaks"}
+{"Time":"2023-07-02T11:00:01Z","Action":"run","Package":"a","Test":"TestBreaks/sub"}
+{"Time":"2023-07-02T11:00:01.1Z","Action":"fail","Package":"a","Test":"TestBreaks/sub","Elapsed":0.1}
+{"Time":"2023-07-02T11:00:01.1Z","Action":"fail","Package":"a","Test":"TestBreaks","Elapsed":0.1}
+{"Time":"2023-07-02T11:00:01.1Z","Action":"run","Package":"a","Test":"TestSlow"}
+{"Time":"2023-07-02T11:00:01.6Z","Action":"pass","Package":"a","Test":"TestSlow","Elapsed":0.5}
+{"Time":"2023-07-02T11:00:01.6Z","Action":"run","Package":"a","Test":"TestNoisy"}
+{"Time":"2023-07-02T11:00:01.61Z","Action":"pass","Package":"a","Test":"TestNoisy","Elapsed":0.01}
+{"Time":"2023-07-02T11:00:01.61Z","Action":"run","Package":"a","Test":"TestNew"}
+{"Time":"2023-07-02T11:00:01.62Z","Action":"fail","Package":"a","Test":"TestNew","Elapsed":0.01}
+{"Time":"2023-07-02T11:00:02Z","Action":"fail","Package":"a","Elapsed":2}`
+
+	baseRun, err := tstat.TestsFromReader(strings.NewReader(base))
+	if err != nil {
+		t.Fatal(err)
+	}
+	headRun, err := tstat.TestsFromReader(strings.NewReader(head))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff := tstat.DiffTestRuns(baseRun, headRun)
+	assert.Equal(t, []string{"a TestBreaks", "a TestBreaks/sub", "a TestNew"}, changeNames(diff.NewFailures))
+	assert.Equal(t, []string{"a TestFixed"}, changeNames(diff.Fixed))
+	assert.Equal(t, []string{"a TestNew"}, changeNames(diff.Added))
+	assert.Equal(t, []string{"a TestRemoved", "gone TestGone"}, changeNames(diff.Removed))
+	assert.Equal(t, []string{"a TestSlow"}, changeNames(diff.Slower))
+	assert.Equal(t, 300*time.Millisecond, diff.Slower[0].Delta())
+	assert.Nil(t, diff.Added[0].Base)
+	assert.Nil(t, diff.Removed[0].Head)
+	assert.Zero(t, diff.Added[0].Delta())
+
+	diff = tstat.DiffTestRuns(baseRun, headRun, tstat.WithSlowdownThreshold(1, 0))
+	assert.Equal(t, []string{"a TestNoisy", "a TestSlow"}, changeNames(diff.Slower))
+}
+
+func TestDiffTestRuns_Same(t *testing.T) {
+	run, err := tstat.Tests("testdata/bigtest.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff := tstat.DiffTestRuns(run, run)
+	assert.Equal(t, tstat.TestRunDiff{}, diff)
+}
+
+func TestDiffTestRuns_ExactNames(t *testing.T) {
+	base := `{"Time":"2023-07-02T10:00:00Z","Action":"start","Package":"a"}
+{"Time":"2023-07-02T10:00:00Z","Action":"run","Package":"a","Test":"TestCase"}
+{"Time":"2023-07-02T10:00:01Z","Action":"pass","Package":"a","Test":"TestCase","Elapsed":1}
+{"Time":"2023-07-02T10:00:01Z","Action":"pass","Package":"a","Elapsed":1}`
+	head := `{"Time":"2023-07-02T11:00:00Z","Action":"start","Package":"a"}
+{"Time":"2023-07-02T11:00:00Z","Action":"run","Package":"a","Test":"Testcase"}
+{"Time":"2023-07-02T11:00:01Z","Action":"fail","Package":"a","Test":"Testcase","Elapsed":1}
+{"Time":"2023-07-02T11:00:01Z","Action":"fail","Package":"a","Elapsed":1}
+{"Time":"2023-07-02T11:00:00Z","Action":"start","Package":"A"}
+{"Time":"2023-07-02T11:00:00Z","Action":"run","Package":"A","Test":"TestCase"}
+{"Time":"2023-07-02T11:00:01Z","Action":"pass","Package":"A","Test":"TestCase","Elapsed":1}
+{"Time":"2023-07-02T11:00:01Z","Action":"pass","Package":"A","Elapsed":1}`
+
+	baseRun, err := tstat.TestsFromReader(strings.NewReader(base))
+	if err != nil {
+		t.Fatal(err)
+	}
+	headRun, err := tstat.TestsFromReader(strings.NewReader(head))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// tests whose names only differ by case are different tests, as are packages.
+	diff := tstat.DiffTestRuns(baseRun, headRun)
+	assert.Equal(t, []string{"A TestCase", "a Testcase"}, changeNames(diff.Added))
+	assert.Equal(t, []string{"a TestCase"}, changeNames(diff.Removed))
+	assert.Equal(t, []string{"a Testcase"}, changeNames(diff.NewFailures))
+	assert.Empty(t, diff.Fixed)
+}
@@ -0,0 +1,207 @@
+package tstat
+
+import (
+	"sort"
+	"time"
+
+	"github.com/nickfiggins/tstat/internal/mathutil"
+)
+
+// RunningTime returns how long the test was running, excluding time spent paused waiting to run in parallel
+// with other tests. It includes the time spent running subtests. If the test ran more than once with -count,
+// the running time of each attempt is added up, without the time other tests ran in between.
+func (t *Test) RunningTime() time.Duration {
+	var running time.Duration
+	for _, s := range t.running() {
+		running += s.duration()
+	}
+	return running
+}
+
+// SelfTime returns how long the test was running while none of its subtests were, which is the time spent
+// in the test's own code. Time spent paused is excluded.
+func (t *Test) SelfTime() time.Duration {
+	var subs []span
+	for _, sub := range t.Subtests {
+		subs = append(subs, sub.running()...)
+	}
+	subs = mergeSpans(subs)
+
+	self := t.RunningTime()
+	for _, s := range t.running() {
+		for _, sub := range subs {
+			self -= s.overlap(sub)
+		}
+	}
+	return self
+}
+
+// SubtestTime returns how long the test was running while at least one of its subtests was running.
+func (t *Test) SubtestTime() time.Duration {
+	return t.RunningTime() - t.SelfTime()
+}
+
+func (s span) duration() time.Duration {
+	return s.end.Sub(s.start)
+}
+
+// overlap returns how long both spans were open at the same time.
+func (s span) overlap(other span) time.Duration {
+	start, end := s.start, s.end
+	if other.start.After(start) {
+		start = other.start
+	}
+	if other.end.Before(end) {
+		end = other.end
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
+
+// mergeSpans combines overlapping spans, so each period of time is only covered once.
+func mergeSpans(spans []span) []span {
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start.Before(spans[j].start) })
+	var merged []span
+	for _, s := range spans {
+		if n := len(merged); n > 0 && !s.start.After(merged[n-1].end) {
+			if s.end.After(merged[n-1].end) {
+				merged[n-1].end = s.end
+			}
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
+
+// Slowest returns up to n of the package's tests, including subtests, with the longest running time.
+// Time spent paused waiting to run in parallel isn't counted, since it doesn't reflect the test's own speed.
+// If n isn't positive, no tests are returned.
+func (pr *PackageRun) Slowest(n int) []*Test {
+	return slowest(flatten(pr.Tests), n)
+}
+
+// Slowest returns up to n tests from all packages, including subtests, with the longest running time.
+// Time spent paused waiting to run in parallel isn't counted, since it doesn't reflect the test's own speed.
+// If n isn't positive, no tests are returned.
+func (tr *TestRun) Slowest(n int) []*Test {
+	var tests []*Test
+	for _, pkg := range tr.pkgs {
+		tests = append(tests, flatten(pkg.Tests)...)
+	}
+	return slowest(tests, n)
+}
+
+func slowest(tests []*Test, n int) []*Test {
+	sort.SliceStable(tests, func(i, j int) bool {
+		ti, tj := tests[i].RunningTime(), tests[j].RunningTime()
+		if ti != tj {
+			return ti > tj
+		}
+		if tests[i].Package != tests[j].Package {
+			return tests[i].Package < tests[j].Package
+		}
+		return tests[i].FullName < tests[j].FullName
+	})
+	return tests[:max(0, min(n, len(tests)))]
+}
+
+// DurationStats summarizes the running times of a set of top-level tests. Subtests are included in the
+// running time of their parents rather than counted separately.
+type DurationStats struct {
+	Count         int           // Count is the number of tests.
+	Total         time.Duration // Total is the sum of the tests' running times.
+	Min, Max      time.Duration // Min and Max are the shortest and longest running times.
+	Mean          time.Duration // Mean is the average running time.
+	P50, P90, P99 time.Duration // P50, P90 and P99 are percentiles of the running times.
+}
+
+// DurationStats returns statistics about the running times of the package's top-level tests.
+func (pr *PackageRun) DurationStats() DurationStats {
+	return durationStats(pr.Tests)
+}
+
+// DurationStats returns statistics about the running times of the top-level tests in all packages.
+func (tr *TestRun) DurationStats() DurationStats {
+	return durationStats(tr.topLevelTests())
+}
+
+func (tr *TestRun) topLevelTests() []*Test {
+	var tests []*Test
+	for _, pkg := range tr.pkgs {
+		tests = append(tests, pkg.Tests...)
+	}
+	return tests
+}
+
+func durationStats(tests []*Test) DurationStats {
+	if len(tests) == 0 {
+		return DurationStats{}
+	}
+
+	vals := make([]float64, 0, len(tests))
+	stats := DurationStats{Count: len(tests), Min: tests[0].RunningTime()}
+	for _, test := range tests {
+		d := test.RunningTime()
+		vals = append(vals, float64(d))
+		stats.Total += d
+		stats.Min = min(stats.Min, d)
+		stats.Max = max(stats.Max, d)
+	}
+	stats.Mean = time.Duration(mathutil.Mean(vals))
+	stats.P50 = time.Duration(mathutil.Percentile(vals, 50))
+	stats.P90 = time.Duration(mathutil.Percentile(vals, 90))
+	stats.P99 = time.Duration(mathutil.Percentile(vals, 99))
+	return stats
+}
+
+// HistogramBucket is the number of tests with a running time in the range [Min, Max). The last bucket
+// of a histogram has no upper bound, and its Max is 0.
+type HistogramBucket struct {
+	Min, Max time.Duration
+	Count    int
+}
+
+// DefaultHistogramBounds are the bucket boundaries used if none are given to Histogram.
+func DefaultHistogramBounds() []time.Duration {
+	return []time.Duration{
+		time.Millisecond, 10 * time.Millisecond, 100 * time.Millisecond, time.Second, 10 * time.Second,
+	}
+}
+
+// Histogram counts the package's top-level tests by running time, with buckets split at the given bounds.
+// If no bounds are given, DefaultHistogramBounds is used.
+func (pr *PackageRun) Histogram(bounds ...time.Duration) []HistogramBucket {
+	return histogram(pr.Tests, bounds)
+}
+
+// Histogram counts the top-level tests in all packages by running time, with buckets split at the given
+// bounds. If no bounds are given, DefaultHistogramBounds is used.
+func (tr *TestRun) Histogram(bounds ...time.Duration) []HistogramBucket {
+	return histogram(tr.topLevelTests(), bounds)
+}
+
+func histogram(tests []*Test, bounds []time.Duration) []HistogramBucket {
+	if len(bounds) == 0 {
+		bounds = DefaultHistogramBounds()
+	}
+	bounds = append([]time.Duration{}, bounds...)
+	sort.Slice(bounds, func(i, j int) bool { return bounds[i] < bounds[j] })
+
+	buckets := make([]HistogramBucket, 0, len(bounds)+1)
+	var lower time.Duration
+	for _, upper := range bounds {
+		buckets = append(buckets, HistogramBucket{Min: lower, Max: upper})
+		lower = upper
+	}
+	buckets = append(buckets, HistogramBucket{Min: lower})
+
+	for _, test := range tests {
+		d := test.RunningTime()
+		i := sort.Search(len(bounds), func(i int) bool { return d < bounds[i] })
+		buckets[i].Count++
+	}
+	return buckets
+}
@@ -0,0 +1,185 @@
+package tstat_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nickfiggins/tstat"
+	"github.com/stretchr/testify/assert"
+)
+
+// parallelRun has a test with two parallel subtests, which are paused until the parent's function returns
+// after 1s, and a sequential test that takes 0.5s.
+const parallelRun = `{"Time":"2023-07-02T10:00:00Z","Action":"start","Package":"pkg"}
+{"Time":"2023-07-02T10:00:00Z","Action":"run","Package":"pkg","Test":"TestP"}
+{"Time":"2023-07-02T10:00:00Z","Action":"run","Package":"pkg","Test":"TestP/a"}
+{"Time":"2023-07-02T10:00:00Z","Action":"pause","Package":"pkg","Test":"TestP/a"}
+{"Time":"2023-07-02T10:00:00Z","Action":"run","Package":"pkg","Test":"TestP/b"}
+{"Time":"2023-07-02T10:00:00Z","Action":"pause","Package":"pkg","Test":"TestP/b"}
+{"Time":"2023-07-02T10:00:01Z","Action":"cont","Package":"pkg","Test":"TestP/a"}
+{"Time":"2023-07-02T10:00:01Z","Action":"cont","Package":"pkg","Test":"TestP/b"}
+{"Time":"2023-07-02T10:00:02Z","Action":"pass","Package":"pkg","Test":"TestP/b","Elapsed":1}
+{"Time":"2023-07-02T10:00:03Z","Action":"pass","Package":"pkg","Test":"TestP/a","Elapsed":2}
+{"Time":"2023-07-02T10:00:03Z","Action":"pass","Package":"pkg","Test":"TestP","Elapsed":3}
+{"Time":"2023-07-02T10:00:03Z","Action":"run","Package":"pkg","Test":"TestQ"}
+{"Time":"2023-07-02T10:00:03.5Z","Action":"pass","Package":"pkg","Test":"TestQ","Elapsed":0.5}
+{"Time":"2023-07-02T10:00:04Z","Action":"pass","Package":"pkg","Elapsed":4}`
+
+func TestTest_SelfTime(t *testing.T) {
+	run, err := tstat.TestsFromReader(strings.NewReader(parallelRun))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, _ := run.Package("pkg")
+
+	tests := []struct {
+		name        string
+		wantRunning time.Duration
+		wantSelf    time.Duration
+	}{
+		{name: "TestP", wantRunning: 3 * time.Second, wantSelf: time.Second},
+		{name: "TestP/a", wantRunning: 2 * time.Second, wantSelf: 2 * time.Second},
+		{name: "TestP/b", wantRunning: time.Second, wantSelf: time.Second},
+		{name: "TestQ", wantRunning: 500 * time.Millisecond, wantSelf: 500 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test, ok := pkg.Test(tt.name)
+			if !ok {
+				t.Fatalf("test %v not found", tt.name)
+			}
+			assert.Equal(t, tt.wantRunning, test.RunningTime())
+			assert.Equal(t, tt.wantSelf, test.SelfTime())
+			assert.Equal(t, tt.wantRunning-tt.wantSelf, test.SubtestTime())
+		})
+	}
+}
+
+func TestTestRun_Slowest(t *testing.T) {
+	run, err := tstat.TestsFromReader(strings.NewReader(parallelRun))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, test := range run.Slowest(3) {
+		names = append(names, test.FullName)
+	}
+	assert.Equal(t, []string{"TestP", "TestP/a", "TestP/b"}, names)
+	assert.Len(t, run.Slowest(10), 4)
+	assert.Empty(t, run.Slowest(0))
+	assert.Empty(t, run.Slowest(-1))
+
+	pkg, _ := run.Package("pkg")
+	assert.Equal(t, run.Slowest(1), pkg.Slowest(1))
+	assert.Empty(t, pkg.Slowest(-5))
+}
+
+// countRun was run with -count=2, so TestB runs for a minute in between the attempts of TestA. The second
+// attempt of TestB is much faster, e.g. because of a warm cache.
+const countRun = `{"Time":"2023-07-02T10:00:00Z","Action":"start","Package":"pkg"}
+{"Time":"2023-07-02T10:00:00Z","Action":"run","Package":"pkg","Test":"TestA"}
+{"Time":"2023-07-02T10:00:01Z","Action":"pass","Package":"pkg","Test":"TestA","Elapsed":1}
+{"Time":"2023-07-02T10:00:01Z","Action":"run","Package":"pkg","Test":"TestB"}
+{"Time":"2023-07-02T10:01:01Z","Action":"pass","Package":"pkg","Test":"TestB","Elapsed":60}
+{"Time":"2023-07-02T10:01:01Z","Action":"run","Package":"pkg","Test":"TestA"}
+{"Time":"2023-07-02T10:01:02Z","Action":"pass","Package":"pkg","Test":"TestA","Elapsed":1}
+{"Time":"2023-07-02T10:01:02Z","Action":"run","Package":"pkg","Test":"TestB"}
+{"Time":"2023-07-02T10:01:02.5Z","Action":"pass","Package":"pkg","Test":"TestB","Elapsed":0.5}
+{"Time":"2023-07-02T10:01:02.5Z","Action":"pass","Package":"pkg","Elapsed":62.5}`
+
+func TestTest_RunningTime_Count(t *testing.T) {
+	run, err := tstat.TestsFromReader(strings.NewReader(countRun))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, _ := run.Package("pkg")
+
+	testA, _ := pkg.Test("TestA")
+	assert.Equal(t, 2*time.Second, testA.RunningTime())
+	assert.Equal(t, 2*time.Second, testA.SelfTime())
+	testB, _ := pkg.Test("TestB")
+	assert.Equal(t, 60500*time.Millisecond, testB.RunningTime())
+
+	// TestA is faster overall, even though its first and last events are further apart.
+	var names []string
+	for _, test := range run.Slowest(2) {
+		names = append(names, test.FullName)
+	}
+	assert.Equal(t, []string{"TestB", "TestA"}, names)
+}
+
+func TestTestRun_DurationStats(t *testing.T) {
+	run, err := tstat.TestsFromReader(strings.NewReader(parallelRun))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := tstat.DurationStats{
+		Count: 2, Total: 3500 * time.Millisecond,
+		Min: 500 * time.Millisecond, Max: 3 * time.Second, Mean: 1750 * time.Millisecond,
+		P50: 500 * time.Millisecond, P90: 3 * time.Second, P99: 3 * time.Second,
+	}
+	assert.Equal(t, want, run.DurationStats())
+	pkg, _ := run.Package("pkg")
+	assert.Equal(t, want, pkg.DurationStats())
+	empty := tstat.PackageRun{}
+	assert.Equal(t, tstat.DurationStats{}, empty.DurationStats())
+}
+
+func TestTestRun_Histogram(t *testing.T) {
+	run, err := tstat.TestsFromReader(strings.NewReader(parallelRun))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []tstat.HistogramBucket{
+		{Min: 0, Max: time.Second, Count: 1},
+		{Min: time.Second, Count: 1},
+	}, run.Histogram(time.Second))
+
+	buckets := run.Histogram()
+	assert.Len(t, buckets, len(tstat.DefaultHistogramBounds())+1)
+	assert.Equal(t, tstat.HistogramBucket{Min: 100 * time.Millisecond, Max: time.Second, Count: 1}, buckets[3])
+	assert.Equal(t, tstat.HistogramBucket{Min: time.Second, Max: 10 * time.Second, Count: 1}, buckets[4])
+}
+
+func TestTest_Elapsed(t *testing.T) {
+	out := parallelRun + `
+{"Time":"2023-07-02T10:00:04Z","Action":"run","Package":"pkg","Test":"TestLateOutput"}
+{"Time":"2023-07-02T10:00:04.5Z","Action":"output","Package":"pkg","Test":"TestLateOutput","Output":"--- PASS: TestLateOutput (0.10s)\n"}
+{"Time":"2023-07-02T10:00:04.5Z","Action":"pass","Package":"pkg","Test":"TestLateOutput","Elapsed":0.1}
+{"Time":"2023-07-02T10:00:05Z","Action":"run","Package":"pkg","Test":"TestUnfinished"}
+{"Time":"2023-07-02T10:00:06Z","Action":"output","Package":"pkg","Test":"TestUnfinished","Output":"working\n"}`
+	run, err := tstat.TestsFromReader(strings.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, _ := run.Package("pkg")
+
+	tests := []struct {
+		name        string
+		wantElapsed time.Duration
+		wantOK      bool
+		wantWall    time.Duration
+		wantDur     time.Duration
+	}{
+		{name: "TestP/a", wantElapsed: 2 * time.Second, wantOK: true, wantWall: 3 * time.Second, wantDur: 2 * time.Second},
+		{name: "TestLateOutput", wantElapsed: 100 * time.Millisecond, wantOK: true, wantWall: 500 * time.Millisecond, wantDur: 100 * time.Millisecond},
+		{name: "TestUnfinished", wantWall: time.Second, wantDur: time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test, ok := pkg.Test(tt.name)
+			if !ok {
+				t.Fatalf("test %v not found", tt.name)
+			}
+			elapsed, ok := test.Elapsed()
+			assert.Equal(t, tt.wantElapsed, elapsed)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantWall, test.WallTime())
+			assert.Equal(t, tt.wantDur, test.Duration())
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package tstat
+
+import (
+	"sort"
+	"time"
+
+	"github.com/nickfiggins/tstat/internal/gotest"
+)
+
+// Attempt is a single run of a test. Tests are run more than once when the -count flag is greater than 1, in
+// which case the Test combines the results of every attempt.
+type Attempt struct {
+	Status     Status        // Status is the outcome of the attempt.
+	Start, End time.Time     // Start and End are when the attempt started and finished.
+	Elapsed    time.Duration // Elapsed is the elapsed time reported when the attempt finished, if it did.
+	Output     []OutputLine  // Output is the output written during the attempt.
+
+	shard int // shard identifies which of the merged runs the attempt is from, see MergeTestRuns.
+}
+
+// Duration returns how long the attempt took, preferring the elapsed time reported by the testing package
+// over the time between the attempt's first and last events.
+func (a Attempt) Duration() time.Duration {
+	if a.Elapsed > 0 {
+		return a.Elapsed
+	}
+	return a.End.Sub(a.Start)
+}
+
+// withAttempt records the event on the test's current attempt, starting a new attempt each time the test runs.
+func (t *Test) withAttempt(e gotest.Event) {
+	if e.Action == gotest.Run || len(t.attempts) == 0 {
+		t.attempts = append(t.attempts, Attempt{Start: e.Time, End: e.Time})
+	}
+
+	a := &t.attempts[len(t.attempts)-1]
+	switch e.Action { //nolint:exhaustive // other actions don't change the attempt's status
+	case gotest.Pass, gotest.Bench:
+		a.Status, a.Elapsed = StatusPassed, e.ElapsedDuration()
+	case gotest.Fail:
+		a.Status, a.Elapsed = StatusFailed, e.ElapsedDuration()
+	case gotest.Skip:
+		a.Status, a.Elapsed = StatusSkipped, e.ElapsedDuration()
+	case gotest.Out:
+		if e.Output != "" {
+			a.Output = append(a.Output, OutputLine{Time: e.Time, Text: e.Output})
+		}
+	}
+	if a.Start.IsZero() || (!e.Time.IsZero() && e.Time.Before(a.Start)) {
+		a.Start = e.Time
+	}
+	if e.Time.After(a.End) {
+		a.End = e.Time
+	}
+}
+
+// Attempts returns each run of the test, in the order they ran. Unless the test was run with -count
+// greater than 1, there's a single attempt.
+func (t *Test) Attempts() []Attempt {
+	attempts := make([]Attempt, len(t.attempts))
+	copy(attempts, t.attempts)
+
+	// the last attempt may not have finished because the test binary panicked or timed out, or it may
+	// be a benchmark in a package that passed.
+	if n := len(attempts); n > 0 && attempts[n-1].Status == StatusIncomplete {
+		switch {
+		case t.panicked || t.timedOut:
+			attempts[n-1].Status = StatusFailed
+		case t.pkgPassed:
+			attempts[n-1].Status = StatusPassed
+		}
+	}
+	return attempts
+}
+
+// Stability describes whether a test passes or fails consistently across multiple attempts.
+type Stability int
+
+const (
+	// StabilityUnknown means the test never passed or failed, e.g. because it was always skipped.
+	StabilityUnknown Stability = iota
+	// StabilityStablePass means every attempt at the test that finished passed.
+	StabilityStablePass
+	// StabilityStableFail means every attempt at the test that finished failed.
+	StabilityStableFail
+	// StabilityFlaky means the test passed on some attempts and failed on others.
+	StabilityFlaky
+)
+
+func (s Stability) String() string {
+	switch s {
+	case StabilityUnknown:
+		return "unknown"
+	case StabilityStablePass:
+		return "stable-pass"
+	case StabilityStableFail:
+		return "stable-fail"
+	case StabilityFlaky:
+		return "flaky"
+	}
+	return "unknown"
+}
+
+// Stability classifies the test based on its attempts. Skipped and incomplete attempts are ignored.
+func (t *Test) Stability() Stability {
+	var passed, failed int
+	for _, a := range t.Attempts() {
+		switch a.Status { //nolint:exhaustive // skipped and incomplete attempts are ignored
+		case StatusPassed:
+			passed++
+		case StatusFailed:
+			failed++
+		}
+	}
+	return stability(passed, failed)
+}
+
+func stability(passed, failed int) Stability {
+	switch {
+	case passed > 0 && failed > 0:
+		return StabilityFlaky
+	case failed > 0:
+		return StabilityStableFail
+	case passed > 0:
+		return StabilityStablePass
+	}
+	return StabilityUnknown
+}
+
+// FlakyTests returns the tests in the run, including subtests, that both passed and failed when run with
+// -count greater than 1.
+func (tr *TestRun) FlakyTests() []*Test {
+	var flaky []*Test
+	for _, pkg := range tr.pkgs {
+		for _, test := range flatten(pkg.Tests) {
+			if test.Stability() == StabilityFlaky {
+				flaky = append(flaky, test)
+			}
+		}
+	}
+	return flaky
+}
+
+// TestStability is the stability of a test across one or more test runs.
+type TestStability struct {
+	Package   string    // Package is the package the test belongs to.
+	FullName  string    // FullName is the full name of the test, including its parents.
+	Stability Stability // Stability classifies the test based on all of its attempts.
+	Passed    int       // Passed is the number of attempts that passed.
+	Failed    int       // Failed is the number of attempts that failed.
+	Skipped   int       // Skipped is the number of attempts that were skipped.
+	Runs      int       // Runs is the number of test runs the test appeared in.
+}
+
+// ClassifyTests classifies every test and subtest in the runs, such as the results of the same test suite
+// from previous CI builds, based on all of their attempts. Tests are identified by their package and full
+// name, and the results are sorted by package and then by name.
+func ClassifyTests(runs ...TestRun) []TestStability {
+	type key struct{ pkg, name string }
+	byTest := make(map[key]*TestStability)
+	for _, run := range runs {
+		seen := make(map[key]bool)
+		for _, pkg := range run.pkgs {
+			for _, test := range flatten(pkg.Tests) {
+				k := key{pkg: pkg.pkgName, name: test.FullName}
+				ts, ok := byTest[k]
+				if !ok {
+					ts = &TestStability{Package: k.pkg, FullName: k.name}
+					byTest[k] = ts
+				}
+				if !seen[k] {
+					seen[k] = true
+					ts.Runs++
+				}
+				for _, a := range test.Attempts() {
+					switch a.Status {
+					case StatusPassed:
+						ts.Passed++
+					case StatusFailed:
+						ts.Failed++
+					case StatusSkipped:
+						ts.Skipped++
+					case StatusIncomplete:
+					}
+				}
+			}
+		}
+	}
+
+	classified := make([]TestStability, 0, len(byTest))
+	for _, ts := range byTest {
+		ts.Stability = stability(ts.Passed, ts.Failed)
+		classified = append(classified, *ts)
+	}
+	sort.Slice(classified, func(i, j int) bool {
+		if classified[i].Package != classified[j].Package {
+			return classified[i].Package < classified[j].Package
+		}
+		return classified[i].FullName < classified[j].FullName
+	})
+	return classified
+}
@@ -0,0 +1,108 @@
+package tstat_test
+
+import (
+	"testing"
+
+	"github.com/nickfiggins/tstat"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTest_Attempts(t *testing.T) {
+	run, err := tstat.Tests("testdata/flaky.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, ok := run.Package("example.com/fl")
+	if !ok {
+		t.Fatal("package not found")
+	}
+
+	tests := []struct {
+		name          string
+		wantStatuses  []tstat.Status
+		wantStability tstat.Stability
+	}{
+		{"TestFlaky", []tstat.Status{tstat.StatusPassed, tstat.StatusFailed, tstat.StatusPassed}, tstat.StabilityFlaky},
+		{"TestFlaky/sometimes", []tstat.Status{tstat.StatusPassed, tstat.StatusFailed, tstat.StatusPassed}, tstat.StabilityFlaky},
+		{"TestFlaky/always", []tstat.Status{tstat.StatusPassed, tstat.StatusPassed, tstat.StatusPassed}, tstat.StabilityStablePass},
+		{"TestBroken", []tstat.Status{tstat.StatusFailed, tstat.StatusFailed, tstat.StatusFailed}, tstat.StabilityStableFail},
+		{"TestStable", []tstat.Status{tstat.StatusPassed, tstat.StatusPassed, tstat.StatusPassed}, tstat.StabilityStablePass},
+		{"TestSkipped", []tstat.Status{tstat.StatusSkipped, tstat.StatusSkipped, tstat.StatusSkipped}, tstat.StabilityUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test, ok := testByFullName(pkg.Tests, tt.name)
+			if !ok {
+				t.Fatalf("test %v not found", tt.name)
+			}
+			var statuses []tstat.Status
+			for _, a := range test.Attempts() {
+				statuses = append(statuses, a.Status)
+				assert.False(t, a.End.Before(a.Start))
+			}
+			assert.Equal(t, tt.wantStatuses, statuses)
+			assert.Equal(t, tt.wantStability, test.Stability())
+		})
+	}
+
+	sometimes, _ := testByFullName(pkg.Tests, "TestFlaky/sometimes")
+	failed := sometimes.Attempts()[1]
+	assert.Contains(t, failed.Output[len(failed.Output)-2].Text, "unlucky")
+
+	var flaky []string
+	for _, test := range run.FlakyTests() {
+		flaky = append(flaky, test.FullName)
+	}
+	assert.ElementsMatch(t, []string{"TestFlaky", "TestFlaky/sometimes"}, flaky)
+}
+
+func testByFullName(tests []*tstat.Test, name string) (*tstat.Test, bool) {
+	for _, test := range tests {
+		if test.FullName == name {
+			return test, true
+		}
+		if sub, ok := testByFullName(test.Subtests, name); ok {
+			return sub, true
+		}
+	}
+	return nil, false
+}
+
+func TestClassifyTests(t *testing.T) {
+	flaky, err := tstat.Tests("testdata/flaky.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	skips, err := tstat.Tests("testdata/skips.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := tstat.ClassifyTests(flaky, flaky, skips)
+	assert.Len(t, got, 6+skips.Count())
+
+	want := map[string]tstat.TestStability{
+		"TestBroken": {Package: "example.com/fl", FullName: "TestBroken", Stability: tstat.StabilityStableFail, Failed: 6, Runs: 2},
+		"TestFlaky/sometimes": {
+			Package: "example.com/fl", FullName: "TestFlaky/sometimes", Stability: tstat.StabilityFlaky, Passed: 4, Failed: 2, Runs: 2,
+		},
+		"TestSkipped": {Package: "example.com/fl", FullName: "TestSkipped", Stability: tstat.StabilityUnknown, Skipped: 6, Runs: 2},
+	}
+	for _, ts := range got {
+		if w, ok := want[ts.FullName]; ok {
+			assert.Equal(t, w, ts)
+		}
+	}
+
+	for i := 1; i < len(got); i++ {
+		prev, cur := got[i-1], got[i]
+		assert.True(t, prev.Package < cur.Package || (prev.Package == cur.Package && prev.FullName < cur.FullName), "results should be sorted")
+	}
+}
+
+func TestStability_String(t *testing.T) {
+	assert.Equal(t, "stable-pass", tstat.StabilityStablePass.String())
+	assert.Equal(t, "stable-fail", tstat.StabilityStableFail.String())
+	assert.Equal(t, "flaky", tstat.StabilityFlaky.String())
+	assert.Equal(t, "unknown", tstat.StabilityUnknown.String())
+}
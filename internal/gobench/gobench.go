@@ -0,0 +1,101 @@
+package gobench
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const benchPrefix = "Benchmark"
+
+// Result is a single line of benchmark results, e.g.
+// "BenchmarkAdd-8   1000   123 ns/op   16 B/op   1 allocs/op".
+type Result struct {
+	Name       string // Name is the name of the benchmark, without the GOMAXPROCS suffix.
+	Procs      int    // Procs is the GOMAXPROCS suffix, or 1 if there wasn't one.
+	Iterations int64
+	Metrics    []Metric
+}
+
+// Metric is a single value reported by a benchmark, along with its unit.
+type Metric struct {
+	Value float64
+	Unit  string
+}
+
+// ParseLine parses a line of benchmark output. If the line isn't a benchmark result, false is returned.
+func ParseLine(line string) (Result, bool) {
+	fields := strings.Fields(line)
+	// name, iterations, then at least one value and unit pair.
+	if len(fields) < 4 || len(fields)%2 != 0 || !isBenchName(fields[0]) {
+		return Result{}, false
+	}
+
+	iterations, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return Result{}, false
+	}
+
+	metrics := make([]Metric, 0, (len(fields)-2)/2)
+	for i := 2; i < len(fields); i += 2 {
+		val, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return Result{}, false
+		}
+		metrics = append(metrics, Metric{Value: val, Unit: fields[i+1]})
+	}
+
+	name, procs := splitProcs(fields[0])
+	return Result{Name: name, Procs: procs, Iterations: iterations, Metrics: metrics}, true
+}
+
+// isBenchName returns true if the name looks like a benchmark function, using the same rules as `go test`.
+func isBenchName(name string) bool {
+	if !strings.HasPrefix(name, benchPrefix) {
+		return false
+	}
+	if len(name) == len(benchPrefix) {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(name[len(benchPrefix):])
+	return !unicode.IsLower(r)
+}
+
+// splitProcs splits the -N GOMAXPROCS suffix from a benchmark name.
+func splitProcs(name string) (string, int) {
+	i := strings.LastIndex(name, "-")
+	if i == -1 {
+		return name, 1
+	}
+	procs, err := strconv.Atoi(name[i+1:])
+	if err != nil || procs <= 0 {
+		return name, 1
+	}
+	return name[:i], procs
+}
+
+// Scanner parses benchmark results from output that may be written in pieces, since benchmark names are
+// printed before the benchmark runs and the results are printed after it finishes. Output from different tests
+// should be added to different scanners, so their partial lines aren't joined.
+type Scanner struct {
+	partial strings.Builder
+}
+
+// Add adds output to the scanner and returns any benchmark results from the lines it completed.
+func (s *Scanner) Add(output string) []Result {
+	var results []Result
+	for {
+		i := strings.IndexByte(output, '\n')
+		if i == -1 {
+			s.partial.WriteString(output)
+			return results
+		}
+		s.partial.WriteString(output[:i])
+		if res, ok := ParseLine(s.partial.String()); ok {
+			results = append(results, res)
+		}
+		s.partial.Reset()
+		output = output[i+1:]
+	}
+}
@@ -0,0 +1,61 @@
+package gobench
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		have   string
+		want   Result
+		wantOK bool
+	}{
+		{
+			have: "BenchmarkAdd-8   \t 1000\t       123 ns/op\t      16 B/op\t       1 allocs/op\n",
+			want: Result{Name: "BenchmarkAdd", Procs: 8, Iterations: 1000, Metrics: []Metric{
+				{Value: 123, Unit: "ns/op"}, {Value: 16, Unit: "B/op"}, {Value: 1, Unit: "allocs/op"},
+			}},
+			wantOK: true,
+		},
+		{
+			have: "BenchmarkAdd/size-10-16 \t 5 \t 0.25 ns/op \t 12.5 widgets/op",
+			want: Result{Name: "BenchmarkAdd/size-10", Procs: 16, Iterations: 5, Metrics: []Metric{
+				{Value: 0.25, Unit: "ns/op"}, {Value: 12.5, Unit: "widgets/op"},
+			}},
+			wantOK: true,
+		},
+		{
+			have:   "Benchmark 100 1 ns/op",
+			want:   Result{Name: "Benchmark", Procs: 1, Iterations: 100, Metrics: []Metric{{Value: 1, Unit: "ns/op"}}},
+			wantOK: true,
+		},
+		{have: "Benchmarking 100 1 ns/op"},
+		{have: "BenchmarkAdd-8"},
+		{have: "BenchmarkAdd-8 many 1 ns/op"},
+		{have: "BenchmarkAdd-8 100 fast ns/op"},
+		{have: "BenchmarkAdd-8 100 1 ns/op 2"},
+		{have: "=== RUN   BenchmarkAdd"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.have, func(t *testing.T) {
+			got, ok := ParseLine(tt.have)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestScanner_Add(t *testing.T) {
+	s := &Scanner{}
+	assert.Empty(t, s.Add("BenchmarkAdd\n"))
+	assert.Empty(t, s.Add("BenchmarkAdd-8   \t"))
+	got := s.Add("    1000\t       123 ns/op\nBenchmarkSub-8 \t 10 \t 4 ns/op\nBench")
+	assert.Equal(t, []Result{
+		{Name: "BenchmarkAdd", Procs: 8, Iterations: 1000, Metrics: []Metric{{Value: 123, Unit: "ns/op"}}},
+		{Name: "BenchmarkSub", Procs: 8, Iterations: 10, Metrics: []Metric{{Value: 4, Unit: "ns/op"}}},
+	}, got)
+	assert.Empty(t, s.Add("markMul-8 \t 10 \t"))
+	assert.Len(t, s.Add(" 4 ns/op\n"), 1)
+}
@@ -2,6 +2,7 @@ package gocover
 
 import (
 	"io"
+	"slices"
 	"strings"
 
 	"github.com/nickfiggins/tstat/internal/mathutil"
@@ -72,7 +73,11 @@ func ByPackage(profiles []*cover.Profile) []*PackageStatements {
 		packages[pkg].add(prof)
 	}
 
-	return maps.Values(packages)
+	vals := maps.Values(packages)
+	slices.SortFunc(vals, func(a, b *PackageStatements) int {
+		return strings.Compare(a.Package, b.Package)
+	})
+	return vals
 }
 
 func packageFromFileName(fileName string) string {
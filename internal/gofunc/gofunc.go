@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -101,7 +102,11 @@ func ByPackage(output Output) []*PackageFunctions {
 		pkg.add(function)
 	}
 
-	return maps.Values(packages)
+	vals := maps.Values(packages)
+	slices.SortFunc(vals, func(a, b *PackageFunctions) int {
+		return strings.Compare(a.Package, b.Package)
+	})
+	return vals
 }
 
 type PackageFunctions struct {
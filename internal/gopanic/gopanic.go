@@ -0,0 +1,156 @@
+package gopanic
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	panicPrefix     = "panic: "
+	goroutinePrefix = "goroutine "
+	createdPrefix   = "created by "
+	recoveredSuffix = " [recovered]"
+)
+
+// Panic is a panic parsed from the output of a program.
+type Panic struct {
+	Message   string  // Message is the value the program panicked with.
+	Recovered bool    // Recovered is true if the panic was recovered, then panicked again.
+	Goroutine int     // Goroutine is the ID of the goroutine that panicked.
+	Frames    []Frame // Frames is the stack of the goroutine that panicked, most recent call first.
+}
+
+// Frame is a single function call in a stack trace.
+type Frame struct {
+	Function  string // Function is the fully qualified name of the function, without arguments.
+	File      string
+	Line      int
+	CreatedBy bool // CreatedBy is true if the frame is the call that started the goroutine.
+}
+
+// Parse parses the first panic in the lines of output, along with the stack trace of the goroutine
+// that panicked. If there's no panic in the output, false is returned.
+func Parse(lines []string) (Panic, bool) {
+	start := -1
+	for i, line := range lines {
+		if strings.HasPrefix(line, panicPrefix) {
+			start = i
+			break
+		}
+	}
+	if start == -1 {
+		return Panic{}, false
+	}
+
+	msg := strings.TrimRight(strings.TrimPrefix(lines[start], panicPrefix), "\n")
+	p := Panic{Message: strings.TrimSuffix(msg, recoveredSuffix), Recovered: strings.HasSuffix(msg, recoveredSuffix)}
+
+	i := start + 1
+	for ; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(line, goroutinePrefix) {
+			p.Goroutine = goroutineID(line)
+			i++
+			break
+		}
+	}
+
+	for ; i+1 < len(lines); i += 2 {
+		fn, loc := strings.TrimSpace(lines[i]), strings.TrimSpace(lines[i+1])
+		if fn == "" {
+			break
+		}
+		frame, ok := parseFrame(fn, loc)
+		if !ok {
+			break
+		}
+		p.Frames = append(p.Frames, frame)
+	}
+	return p, true
+}
+
+// goroutineID parses the ID from a line like "goroutine 39 [running]:".
+func goroutineID(line string) int {
+	fields := strings.Fields(strings.TrimPrefix(line, goroutinePrefix))
+	if len(fields) == 0 {
+		return 0
+	}
+	id, _ := strconv.Atoi(fields[0])
+	return id
+}
+
+// parseFrame parses a function call and its location, e.g. "pkg.Func(0x1, 0x2)" followed by
+// "/path/to/file.go:12 +0x1d".
+func parseFrame(fn, loc string) (Frame, bool) {
+	frame := Frame{}
+	if strings.HasPrefix(fn, createdPrefix) {
+		frame.CreatedBy = true
+		fn = strings.TrimPrefix(fn, createdPrefix)
+		if i := strings.Index(fn, " in goroutine "); i != -1 {
+			fn = fn[:i]
+		}
+	} else if i := strings.LastIndex(fn, "("); i != -1 && strings.HasSuffix(fn, ")") {
+		fn = fn[:i]
+	}
+	frame.Function = fn
+
+	if i := strings.LastIndex(loc, " +0x"); i != -1 {
+		loc = loc[:i]
+	}
+	sep := strings.LastIndex(loc, ":")
+	if sep == -1 {
+		return Frame{}, false
+	}
+	line, err := strconv.Atoi(loc[sep+1:])
+	if err != nil {
+		return Frame{}, false
+	}
+	frame.File, frame.Line = loc[:sep], line
+	return frame, true
+}
+
+const (
+	timeoutPrefix = "panic: test timed out after "
+	runningTests  = "running tests:"
+)
+
+// Timeout is a panic from a test binary running longer than its -timeout.
+type Timeout struct {
+	After   time.Duration // After is the timeout that was exceeded.
+	Running []string      // Running is the full names of the tests that were still running.
+}
+
+// ParseTimeout parses a timeout panic from the lines of output, which lists the tests that were running
+// when the timeout was exceeded. If there's no timeout in the output, false is returned.
+func ParseTimeout(lines []string) (Timeout, bool) {
+	start := -1
+	for i, line := range lines {
+		if strings.HasPrefix(line, timeoutPrefix) {
+			start = i
+			break
+		}
+	}
+	if start == -1 {
+		return Timeout{}, false
+	}
+
+	after, _ := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(lines[start], timeoutPrefix)))
+	timeout := Timeout{After: after}
+	if start+1 >= len(lines) || strings.TrimSpace(lines[start+1]) != runningTests {
+		return timeout, true
+	}
+
+	for _, line := range lines[start+2:] {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			break
+		}
+		// e.g. "TestHang/sub (10m0s)"
+		if i := strings.LastIndex(line, " ("); i != -1 {
+			line = line[:i]
+		}
+		timeout.Running = append(timeout.Running, line)
+	}
+	return timeout, true
+}
@@ -0,0 +1,126 @@
+package gopanic
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		have   string
+		want   Panic
+		wantOK bool
+	}{
+		{
+			name: "recovered in test",
+			have: `=== RUN   TestAdd
+panic: panicked [recovered]
+	panic: panicked
+
+goroutine 39 [running]:
+testing.tRunner.func1.2({0x139a7c0, 0x1473018})
+	/usr/local/go/src/testing/testing.go:1526 +0x372
+panic({0x139a7c0, 0x1473018})
+	/usr/local/go/src/runtime/panic.go:890 +0x263
+github.com/nickfiggins/tstat_test.Test_CoverageStatsFromReaders.func1(0xc000082680)
+	/Users/nickfiggins/dev/tstat/tstat_test.go:71 +0x113
+created by testing.(*T).Run in goroutine 1
+	/usr/local/go/src/testing/testing.go:1629 +0x806
+
+goroutine 1 [chan receive]:
+testing.(*T).Run(0x247760d8008, {0x554bcd?, 0x24776090aa0?}, 0x6d4880)
+	/usr/local/go/src/testing/testing.go:2266 +0x4f2
+`,
+			want: Panic{
+				Message: "panicked", Recovered: true, Goroutine: 39,
+				Frames: []Frame{
+					{Function: "testing.tRunner.func1.2", File: "/usr/local/go/src/testing/testing.go", Line: 1526},
+					{Function: "panic", File: "/usr/local/go/src/runtime/panic.go", Line: 890},
+					{Function: "github.com/nickfiggins/tstat_test.Test_CoverageStatsFromReaders.func1", File: "/Users/nickfiggins/dev/tstat/tstat_test.go", Line: 71},
+					{Function: "testing.(*T).Run", File: "/usr/local/go/src/testing/testing.go", Line: 1629, CreatedBy: true},
+				},
+			},
+			wantOK: true,
+		},
+		{
+			name: "init panic, inlined",
+			have: `panic: bad config
+
+goroutine 1 [running]:
+example.com/bf/initpanic.init.func1(...)
+	/tmp/bf/initpanic/init_test.go:5
+example.com/bf/initpanic.init()
+	/tmp/bf/initpanic/init_test.go:5 +0x25
+FAIL	example.com/bf/initpanic	0.006s
+`,
+			want: Panic{
+				Message: "bad config", Goroutine: 1,
+				Frames: []Frame{
+					{Function: "example.com/bf/initpanic.init.func1", File: "/tmp/bf/initpanic/init_test.go", Line: 5},
+					{Function: "example.com/bf/initpanic.init", File: "/tmp/bf/initpanic/init_test.go", Line: 5},
+				},
+			},
+			wantOK: true,
+		},
+		{
+			name: "no stack",
+			have: "panic: oops\n",
+			want: Panic{Message: "oops"}, wantOK: true,
+		},
+		{
+			name: "no panic",
+			have: "=== RUN   TestAdd\n--- PASS: TestAdd (0.00s)\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Parse(strings.SplitAfter(tt.have, "\n"))
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseTimeout(t *testing.T) {
+	tests := []struct {
+		name   string
+		have   string
+		want   Timeout
+		wantOK bool
+	}{
+		{
+			name: "running tests",
+			have: `=== RUN   TestHang/sub
+panic: test timed out after 10m0s
+	running tests:
+		TestHang (10m0s)
+		TestHang/sub (9m59s)
+
+goroutine 9 [running]:
+`,
+			want:   Timeout{After: 10 * time.Minute, Running: []string{"TestHang", "TestHang/sub"}},
+			wantOK: true,
+		},
+		{
+			name:   "no running tests",
+			have:   "panic: test timed out after 1s\n\ngoroutine 9 [running]:\n",
+			want:   Timeout{After: time.Second},
+			wantOK: true,
+		},
+		{
+			name: "regular panic",
+			have: "panic: oops\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseTimeout(strings.SplitAfter(tt.have, "\n"))
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package gotest
 
 import (
 	"encoding/json"
+	"math"
 	"strconv"
 	"strings"
 	"time"
@@ -14,6 +15,81 @@ type Event struct {
 	Test    string    `json:"Test"`
 	Package string    `json:"Package"`
 	Elapsed float64   `json:"Elapsed"` // Elapsed is the number of seconds that have passed.
+
+	// ImportPath is the package being built, only set for build events (Go 1.24+). It may include
+	// the test binary being built, e.g. "pkg [pkg.test]".
+	ImportPath string `json:"ImportPath,omitempty"`
+	// FailedBuild is the ImportPath of the build that failed, set on a package's fail event when
+	// it failed because of a build error (Go 1.24+).
+	FailedBuild string `json:"FailedBuild,omitempty"`
+	// OutputType describes how the output was written, e.g. "frame" for the "=== RUN" and "--- PASS" lines
+	// written by the testing package (Go 1.25+).
+	OutputType string `json:"OutputType,omitempty"`
+
+	// RawAction is the action as it was written, only populated if the action isn't one that's known.
+	RawAction string `json:"-"`
+	// Raw is the event as it was written, only populated if the action isn't one that's known, so that fields
+	// specific to the action, such as the Key and Value of "attr" events, aren't lost when it's written back.
+	Raw json.RawMessage `json:"-"`
+}
+
+func (e *Event) UnmarshalJSON(b []byte) error {
+	type plain Event
+	var raw struct {
+		plain
+		Action string `json:"Action"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	*e = Event(raw.plain)
+	e.Action = ToAction(raw.Action)
+	if e.Action == Undefined {
+		e.RawAction = raw.Action
+		e.Raw = append(json.RawMessage(nil), b...)
+	}
+	return nil
+}
+
+// MarshalJSON writes the event the same way test2json does, omitting fields that don't apply to the action.
+// Events with an action that isn't known are written the way they were read.
+func (e Event) MarshalJSON() ([]byte, error) {
+	if e.Action == Undefined && len(e.Raw) > 0 {
+		return e.Raw, nil
+	}
+	out := struct {
+		ImportPath  string     `json:"ImportPath,omitempty"`
+		Time        *time.Time `json:"Time,omitempty"`
+		Action      string     `json:"Action"`
+		Package     string     `json:"Package,omitempty"`
+		Test        string     `json:"Test,omitempty"`
+		Elapsed     *float64   `json:"Elapsed,omitempty"`
+		Output      *string    `json:"Output,omitempty"`
+		OutputType  string     `json:"OutputType,omitempty"`
+		FailedBuild string     `json:"FailedBuild,omitempty"`
+	}{
+		ImportPath: e.ImportPath, Action: e.Action.String(), Package: e.Package, Test: e.Test,
+		OutputType: e.OutputType, FailedBuild: e.FailedBuild,
+	}
+	if !e.Time.IsZero() {
+		out.Time = &e.Time
+	}
+	if e.Action == Undefined && e.RawAction != "" {
+		out.Action = e.RawAction
+	}
+	// test2json always reports the elapsed time of final events, even if it's 0.
+	if e.Action.IsFinal() || e.Elapsed != 0 {
+		out.Elapsed = &e.Elapsed
+	}
+	if e.Action == Out || e.Action == BuildOutput || e.Output != "" {
+		out.Output = &e.Output
+	}
+	return json.Marshal(out)
+}
+
+// ElapsedDuration returns the Elapsed field as a duration.
+func (e *Event) ElapsedDuration() time.Duration {
+	return time.Duration(math.Round(e.Elapsed * float64(time.Second)))
 }
 
 func (e *Event) Seed() (int64, bool) {
@@ -32,26 +108,57 @@ func (e *Event) Seed() (int64, bool) {
 
 // PackageEvent returns true if the event is a package event (no test referenced in event).
 func (e *Event) PackageEvent() bool {
-	return e.Test == "" && e.Package != ""
+	return e.Test == "" && e.PackageName() != ""
+}
+
+// PackageName returns the package that the event belongs to. For build events, which don't have a package,
+// it's derived from the import path of the test binary being built. Build events for a package that isn't
+// being tested, such as a dependency, don't belong to any package, see DependencyBuild.
+func (e *Event) PackageName() string {
+	if e.Package != "" || e.ImportPath == "" {
+		return e.Package
+	}
+
+	// e.g. "pkg_test [pkg.test]", where the package is the test binary without the .test suffix.
+	path := e.ImportPath
+	if start, end := strings.Index(path, "["), strings.LastIndex(path, "]"); start != -1 && end > start {
+		path = path[start+1 : end]
+		return strings.TrimSuffix(path, ".test")
+	}
+	return ""
+}
+
+// DependencyBuild returns true if the event is from building a package that isn't a test binary, e.g. a
+// dependency of the package being tested. These events belong to the packages whose fail event names the
+// import path in FailedBuild.
+func (e *Event) DependencyBuild() bool {
+	return e.Package == "" && e.ImportPath != "" && !strings.Contains(e.ImportPath, "[")
 }
 
 // see https://pkg.go.dev/cmd/test2json#hdr-Output_Format
 type Action int
 
 const (
-	Start     Action = 0
-	Pass      Action = 1
-	Fail      Action = 2
-	Skip      Action = 3
-	Out       Action = 4
-	Run       Action = 5
-	Undefined Action = -1
+	Start       Action = 0
+	Pass        Action = 1
+	Fail        Action = 2
+	Skip        Action = 3
+	Out         Action = 4
+	Run         Action = 5
+	Pause       Action = 6
+	Cont        Action = 7
+	Bench       Action = 8
+	BuildOutput Action = 9  // BuildOutput is output from building the test binary (Go 1.24+).
+	BuildFail   Action = 10 // BuildFail is emitted when building the test binary fails (Go 1.24+).
+	Undefined   Action = -1
 )
 
 func ToAction(s string) Action {
 	toAction := map[string]Action{
 		"start": Start, "pass": Pass, "fail": Fail, "skip": Skip,
-		"output": Out, "run": Run, "undefined": Undefined,
+		"output": Out, "run": Run, "pause": Pause, "cont": Cont,
+		"bench": Bench, "build-output": BuildOutput, "build-fail": BuildFail,
+		"undefined": Undefined,
 	}
 	a, ok := toAction[strings.ToLower(s)]
 	if !ok {
@@ -64,7 +171,9 @@ func ToAction(s string) Action {
 func (a Action) String() string {
 	toStr := map[Action]string{
 		Start: "start", Pass: "pass", Fail: "fail", Skip: "skip",
-		Out: "output", Run: "run", Undefined: "undefined",
+		Out: "output", Run: "run", Pause: "pause", Cont: "cont",
+		Bench: "bench", BuildOutput: "build-output", BuildFail: "build-fail",
+		Undefined: "undefined",
 	}
 	s, ok := toStr[a]
 	if !ok {
@@ -89,9 +198,9 @@ func (a Action) MarshalJSON() ([]byte, error) {
 
 func (a Action) IsFinal() bool {
 	switch a {
-	case Pass, Fail, Skip:
+	case Pass, Fail, Skip, Bench:
 		return true
-	case Start, Run, Out, Undefined:
+	case Start, Run, Out, Pause, Cont, BuildOutput, BuildFail, Undefined:
 	default:
 	}
 	return false
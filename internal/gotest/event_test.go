@@ -1,8 +1,11 @@
 package gotest
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func Test_ToAction(t *testing.T) {
@@ -13,6 +16,8 @@ func Test_ToAction(t *testing.T) {
 		{"PASS", Pass}, {"FAIL", Fail}, {"fAiL", Fail},
 		{"output", Out}, {"skip", Skip}, {"start", Start},
 		{"dfioroiriooi", Undefined}, {"undefined", Undefined},
+		{"pause", Pause}, {"cont", Cont}, {"bench", Bench},
+		{"build-output", BuildOutput}, {"build-fail", BuildFail},
 	}
 	for _, tt := range tests {
 		if got := ToAction(tt.have); got != tt.want {
@@ -31,6 +36,9 @@ func TestAction_String(t *testing.T) {
 		{Out, "output"},
 		{Skip, "skip"},
 		{Start, "start"},
+		{Pause, "pause"},
+		{Cont, "cont"},
+		{Bench, "bench"},
 		{Action(-1), "undefined"},
 	}
 	for _, tt := range tests {
@@ -50,6 +58,9 @@ func TestAction_IsFinal(t *testing.T) {
 		{Out, false},
 		{Skip, true},
 		{Start, false},
+		{Pause, false},
+		{Cont, false},
+		{Bench, true},
 		{Action(-1), false},
 	}
 	for _, tt := range tests {
@@ -143,3 +154,87 @@ func TestEvent_Seed(t *testing.T) {
 		})
 	}
 }
+
+func TestEvent_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		have    string
+		want    Event
+		wantErr bool
+	}{
+		{
+			have: `{"Action":"pause","Package":"pkg","Test":"TestAdd"}`,
+			want: Event{Action: Pause, Package: "pkg", Test: "TestAdd"},
+		},
+		{
+			have: `{"Action":"cont","Package":"pkg","Test":"TestAdd","Elapsed":1.5}`,
+			want: Event{Action: Cont, Package: "pkg", Test: "TestAdd", Elapsed: 1.5},
+		},
+		{
+			have: `{"Action":"teleport","Package":"pkg"}`,
+			want: Event{
+				Action: Undefined, Package: "pkg", RawAction: "teleport",
+				Raw: json.RawMessage(`{"Action":"teleport","Package":"pkg"}`),
+			},
+		},
+		{
+			have:    `{"Action":1}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.have, func(t *testing.T) {
+			var got Event
+			err := json.Unmarshal([]byte(tt.have), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEvent_PackageName(t *testing.T) {
+	tests := []struct {
+		have Event
+		want string
+	}{
+		{have: Event{Package: "pkg"}, want: "pkg"},
+		{have: Event{Package: "pkg", ImportPath: "other [other.test]"}, want: "pkg"},
+		{have: Event{ImportPath: "example.com/pkg [example.com/pkg.test]"}, want: "example.com/pkg"},
+		{have: Event{ImportPath: "example.com/pkg_test [example.com/pkg.test]"}, want: "example.com/pkg"},
+		{have: Event{ImportPath: "example.com/pkg"}, want: ""},
+		{have: Event{}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.have.PackageName())
+			assert.Equal(t, tt.want != "", tt.have.PackageEvent())
+		})
+	}
+}
+
+func TestEvent_MarshalJSON(t *testing.T) {
+	lines := []string{
+		`{"Time":"2023-07-02T14:05:10.123456789Z","Action":"start","Package":"pkg"}`,
+		`{"Time":"2023-07-02T14:05:10.2Z","Action":"output","Package":"pkg","Test":"TestA","Output":"=== RUN   TestA\n","OutputType":"frame"}`,
+		`{"Time":"2023-07-02T14:05:10.3Z","Action":"pass","Package":"pkg","Test":"TestA","Elapsed":0}`,
+		`{"ImportPath":"pkg [pkg.test]","Action":"build-output","Output":"# pkg\n"}`,
+		`{"Time":"2023-07-02T14:05:10.4Z","Action":"fail","Package":"pkg","Elapsed":0.01,"FailedBuild":"pkg [pkg.test]"}`,
+		`{"Time":"2023-07-02T14:05:10.5Z","Action":"attach","Package":"pkg","Test":"TestA"}`,
+		`{"Time":"2023-07-02T14:05:10.6Z","Action":"attr","Package":"pkg","Test":"TestA","Key":"owner","Value":"team"}`,
+	}
+	for _, line := range lines {
+		var e Event
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatal(err)
+		}
+		got, err := json.Marshal(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, line, string(got))
+	}
+}
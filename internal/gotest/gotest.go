@@ -5,30 +5,58 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"slices"
 	"strings"
 	"unicode"
 )
 
 func readJSON(r io.Reader) ([]Event, error) {
-	sc := bufio.NewScanner(r)
 	var lines []Event
+	err := ReadEach(r, func(e Event) error {
+		lines = append(lines, e)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// ReadEach decodes events from r one line at a time, calling fn for each event as soon as it's read.
+// Reading stops at the first error returned by fn, which is returned as is.
+func ReadEach(r io.Reader, fn func(Event) error) error {
+	sc := bufio.NewScanner(r)
 	for sc.Scan() {
-		var line Event
-		s := strings.TrimFunc(sc.Text(), unicode.IsSpace)
-		if len(s) == 0 {
+		line, ok, err := ParseLine(sc.Bytes())
+		if err != nil {
+			return err
+		}
+		if !ok {
 			continue
 		}
-		err := json.Unmarshal([]byte(s), &line)
-		if err != nil {
-			return nil, fmt.Errorf("couldn't unmarshal json: %w, bytes: %v", err, s)
+		if err := fn(line); err != nil {
+			return err
 		}
-		lines = append(lines, line)
 	}
 	err := sc.Err()
 	if err != nil {
-		return nil, fmt.Errorf("error while scanning: %w", err)
+		return fmt.Errorf("error while scanning: %w", err)
 	}
-	return lines, nil
+	return nil
+}
+
+// ParseLine decodes a single line of JSON output. If the line is blank, false is returned.
+func ParseLine(b []byte) (Event, bool, error) {
+	var line Event
+	s := strings.TrimFunc(string(b), unicode.IsSpace)
+	if len(s) == 0 {
+		return Event{}, false, nil
+	}
+	err := json.Unmarshal([]byte(s), &line)
+	if err != nil {
+		return Event{}, false, fmt.Errorf("couldn't unmarshal json: %w, bytes: %v", err, s)
+	}
+	return line, true, nil
 }
 
 func ReadByPackage(r io.Reader) ([]*PackageEvents, error) {
@@ -39,15 +67,23 @@ func ReadByPackage(r io.Reader) ([]*PackageEvents, error) {
 	return ByPackage(events), nil
 }
 
+// ByPackage groups the events by the package they belong to. Events from building a dependency are added to
+// the start of the events of each package whose build failed because of it, and are dropped otherwise.
 func ByPackage(events []Event) []*PackageEvents {
 	packages := make(map[string]*PackageEvents)
+	builds := make(map[string][]Event)
 	for _, e := range events {
-		if e.Package == "" {
+		if e.DependencyBuild() {
+			builds[e.ImportPath] = append(builds[e.ImportPath], e)
+			continue
+		}
+		name := e.PackageName()
+		if name == "" {
 			continue
 		}
-		pkg, ok := packages[e.Package]
+		pkg, ok := packages[name]
 		if !ok {
-			packages[e.Package] = newFromEvent(e)
+			packages[name] = newFromEvent(e)
 			continue
 		}
 		pkg.Add(e)
@@ -55,12 +91,43 @@ func ByPackage(events []Event) []*PackageEvents {
 
 	vals := make([]*PackageEvents, 0, len(packages))
 	for _, v := range packages {
+		if v.End != nil && len(builds[v.End.FailedBuild]) > 0 {
+			v.Events = append(slices.Clip(builds[v.End.FailedBuild]), v.Events...)
+		}
 		vals = append(vals, v)
 	}
+	slices.SortFunc(vals, func(a, b *PackageEvents) int {
+		return strings.Compare(a.Package, b.Package)
+	})
 
 	return vals
 }
 
+// MergeByTime merges lists of events into a single list ordered by time. The order of the events within each
+// list is kept, and events with the same time are taken from the earlier list first.
+func MergeByTime(lists ...[]Event) []Event {
+	var n int
+	for _, l := range lists {
+		n += len(l)
+	}
+	merged := make([]Event, 0, n)
+	next := make([]int, len(lists))
+	for len(merged) < n {
+		pick := -1
+		for i, l := range lists {
+			if next[i] == len(l) {
+				continue
+			}
+			if pick == -1 || l[next[i]].Time.Before(lists[pick][next[pick]].Time) {
+				pick = i
+			}
+		}
+		merged = append(merged, lists[pick][next[pick]])
+		next[pick]++
+	}
+	return merged
+}
+
 type PackageEvents struct {
 	Package    string
 	Start, End *Event
@@ -73,7 +140,7 @@ func (pe *PackageEvents) Add(e Event) {
 		pe.Start = &e
 	}
 
-	if pe.End == nil && e.Elapsed != 0 && e.Test == "" {
+	if pe.End == nil && (e.Elapsed != 0 || e.Action.IsFinal()) && e.Test == "" {
 		pe.End = &e
 	}
 
@@ -89,7 +156,7 @@ func (pe *PackageEvents) Add(e Event) {
 
 func newFromEvent(e Event) *PackageEvents {
 	pe := &PackageEvents{
-		Package: e.Package,
+		Package: e.PackageName(),
 		Start:   nil, End: nil,
 		Events: []Event{},
 	}
@@ -52,6 +52,28 @@ func TestReadByPackage(t *testing.T) {
 				}},
 			},
 		},
+		{
+			name: "build failure",
+			have: strings.NewReader(`
+			{"ImportPath":"pkg [pkg.test]","Action":"build-output","Output":"# pkg [pkg.test]\n"}
+			{"ImportPath":"pkg [pkg.test]","Action":"build-fail"}
+			{"Time":"2023-05-13T21:30:15.409912-04:00","Action":"start","Package":"pkg"}
+			{"Time":"2023-05-13T21:30:15.59089-04:00","Action":"fail","Package":"pkg","Elapsed":0,"FailedBuild":"pkg [pkg.test]"}
+			`),
+			want: []*PackageEvents{
+				{
+					Package: "pkg",
+					Start:   &Event{Time: format(t, "2023-05-13T21:30:15.409912-04:00"), Action: Start, Package: "pkg"},
+					End:     &Event{Time: format(t, "2023-05-13T21:30:15.59089-04:00"), Action: Fail, Package: "pkg", FailedBuild: "pkg [pkg.test]"},
+					Events: []Event{
+						{ImportPath: "pkg [pkg.test]", Action: BuildOutput, Output: "# pkg [pkg.test]\n"},
+						{ImportPath: "pkg [pkg.test]", Action: BuildFail},
+						{Time: format(t, "2023-05-13T21:30:15.409912-04:00"), Action: Start, Package: "pkg"},
+						{Time: format(t, "2023-05-13T21:30:15.59089-04:00"), Action: Fail, Package: "pkg", FailedBuild: "pkg [pkg.test]"},
+					},
+				},
+			},
+		},
 		{
 			name:    "error reading",
 			have:    &errReader{},
@@ -203,3 +225,65 @@ func mockEvents(t *testing.T) []Event {
 		},
 	}
 }
+
+func TestReadEach(t *testing.T) {
+	have := strings.NewReader(`
+		{"Action":"run","Package":"pkg","Test":"TestAdd"}
+
+		{"Action":"pass","Package":"pkg","Test":"TestAdd"}
+		{"Action":"pass","Package":"pkg"}
+		`)
+	var got []Action
+	stopErr := io.ErrShortBuffer
+	err := ReadEach(have, func(e Event) error {
+		got = append(got, e.Action)
+		if e.Test == "" {
+			return stopErr
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, stopErr)
+	assert.Equal(t, []Action{Run, Pass, Pass}, got)
+
+	err = ReadEach(strings.NewReader(`{"bad": "json}`), func(Event) error { return nil })
+	assert.Error(t, err)
+}
+
+func TestMergeByTime(t *testing.T) {
+	at := func(sec int) time.Time { return time.Date(2023, 7, 2, 10, 0, sec, 0, time.UTC) }
+	a := []Event{
+		{Time: at(0), Package: "a", Action: Start},
+		{Time: at(2), Package: "a", Output: "first"},
+		{Time: at(1), Package: "a", Output: "out of order"},
+		{Time: at(3), Package: "a", Action: Pass},
+	}
+	b := []Event{
+		{Time: at(1), Package: "b", Action: Start},
+		{Time: at(2), Package: "b", Action: Pass},
+	}
+
+	got := MergeByTime(a, b, nil)
+	want := []Event{a[0], b[0], a[1], a[2], b[1], a[3]}
+	assert.Equal(t, want, got)
+	assert.Empty(t, MergeByTime())
+}
+
+func TestByPackage_DependencyBuild(t *testing.T) {
+	events := []Event{
+		{ImportPath: "example.com/dep", Action: BuildOutput, Output: "# example.com/dep\n"},
+		{ImportPath: "example.com/dep", Action: BuildFail},
+		{ImportPath: "example.com/unused", Action: BuildOutput, Output: "# example.com/unused\n"},
+		{Package: "example.com/a", Action: Start},
+		{Package: "example.com/a", Action: Fail, FailedBuild: "example.com/dep"},
+		{Package: "example.com/b", Action: Start},
+		{Package: "example.com/b", Action: Pass},
+	}
+
+	got := ByPackage(events)
+	if !assert.Len(t, got, 2) {
+		return
+	}
+	assert.Equal(t, "example.com/a", got[0].Package)
+	assert.Equal(t, append(events[:2:2], events[3:5]...), got[0].Events)
+	assert.Equal(t, events[5:], got[1].Events)
+}
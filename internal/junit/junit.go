@@ -0,0 +1,95 @@
+package junit
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// Testsuites is the root element of a JUnit XML report.
+type Testsuites struct {
+	XMLName  xml.Name    `xml:"testsuites"`
+	Name     string      `xml:"name,attr,omitempty"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Errors   int         `xml:"errors,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Time     string      `xml:"time,attr,omitempty"`
+	Suites   []Testsuite `xml:"testsuite"`
+}
+
+// Testsuite is a group of test cases, one per Go package.
+type Testsuite struct {
+	Name       string     `xml:"name,attr"`
+	Tests      int        `xml:"tests,attr"`
+	Failures   int        `xml:"failures,attr"`
+	Errors     int        `xml:"errors,attr"`
+	Skipped    int        `xml:"skipped,attr"`
+	ID         int        `xml:"id,attr"`
+	Time       string     `xml:"time,attr,omitempty"`
+	Timestamp  string     `xml:"timestamp,attr,omitempty"`
+	Properties []Property `xml:"properties>property,omitempty"`
+	Testcases  []Testcase `xml:"testcase"`
+	SystemOut  *Output    `xml:"system-out,omitempty"`
+}
+
+// Property is a name and value pair describing a test suite.
+type Property struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// Testcase is a single test.
+type Testcase struct {
+	Name      string  `xml:"name,attr"`
+	Classname string  `xml:"classname,attr"`
+	Time      string  `xml:"time,attr,omitempty"`
+	Failure   *Result `xml:"failure,omitempty"`
+	Error     *Result `xml:"error,omitempty"`
+	Skipped   *Result `xml:"skipped,omitempty"`
+	SystemOut *Output `xml:"system-out,omitempty"`
+}
+
+// Result describes why a test case didn't pass.
+type Result struct {
+	Message string `xml:"message,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+	Data    string `xml:",cdata"`
+}
+
+// Output is text written during a test case or test suite.
+type Output struct {
+	Data string `xml:",cdata"`
+}
+
+// Read decodes a JUnit XML report and returns its test suites. The root element may either be <testsuites>
+// or a single <testsuite>.
+func Read(r io.Reader) ([]Testsuite, error) {
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("couldn't find root element: %w", err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch start.Name.Local {
+		case "testsuites":
+			var suites Testsuites
+			if err := dec.DecodeElement(&suites, &start); err != nil {
+				return nil, fmt.Errorf("couldn't decode test suites: %w", err)
+			}
+			return suites.Suites, nil
+		case "testsuite":
+			var suite Testsuite
+			if err := dec.DecodeElement(&suite, &start); err != nil {
+				return nil, fmt.Errorf("couldn't decode test suite: %w", err)
+			}
+			return []Testsuite{suite}, nil
+		}
+		return nil, fmt.Errorf("unexpected root element: %v", start.Name.Local)
+	}
+}
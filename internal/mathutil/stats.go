@@ -0,0 +1,221 @@
+package mathutil
+
+import (
+	"math"
+	"slices"
+)
+
+// Mean returns the arithmetic mean of the values, or 0 if there are none.
+func Mean(vals []float64) float64 {
+	if len(vals) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range vals {
+		sum += v
+	}
+	return sum / float64(len(vals))
+}
+
+// Median returns the median of the values, or 0 if there are none.
+func Median(vals []float64) float64 {
+	if len(vals) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(vals)
+	slices.Sort(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// Percentile returns the p-th percentile of the values, using the nearest-rank method so the result is always
+// one of the values. p is between 0 and 100. If there are no values, 0 is returned.
+func Percentile(vals []float64, p float64) float64 {
+	if len(vals) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(vals)
+	slices.Sort(sorted)
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	rank = min(max(rank, 1), len(sorted))
+	return sorted[rank-1]
+}
+
+// MedianCI returns a distribution-free confidence interval for the median of the values, using order
+// statistics. The interval is the narrowest one with at least the given confidence. If there are too few
+// values to reach that confidence, the range of the values is returned. The actual confidence of the
+// returned interval is returned as the last value.
+func MedianCI(vals []float64, confidence float64) (float64, float64, float64) {
+	if len(vals) == 0 {
+		return 0, 0, 0
+	}
+	sorted := slices.Clone(vals)
+	slices.Sort(sorted)
+	n := len(sorted)
+
+	// the interval [x(k), x(n-k+1)] covers the median with probability 1 - 2*P(B <= k-1)
+	// where B ~ Binomial(n, 0.5).
+	k, achieved := 1, 1-2*binomCDF(0, n)
+	for i := n / 2; i >= 1; i-- {
+		c := 1 - 2*binomCDF(i-1, n)
+		if c >= confidence {
+			k, achieved = i, c
+			break
+		}
+	}
+	return sorted[k-1], sorted[n-k], achieved
+}
+
+// binomCDF returns P(B <= k) where B ~ Binomial(n, 0.5).
+func binomCDF(k, n int) float64 {
+	var p float64
+	for i := 0; i <= k; i++ {
+		p += math.Exp(lchoose(n, i) - float64(n)*math.Ln2)
+	}
+	return p
+}
+
+func lchoose(n, k int) float64 {
+	a, _ := math.Lgamma(float64(n + 1))
+	b, _ := math.Lgamma(float64(k + 1))
+	c, _ := math.Lgamma(float64(n - k + 1))
+	return a - b - c
+}
+
+// maxExact is the largest sample size for which the exact distribution of U is used.
+const maxExact = 50
+
+// MannWhitneyU returns the two-sided p-value of a Mann-Whitney U test, which tests whether values in
+// one sample tend to be larger than values in the other, without assuming they're normally distributed.
+// If either sample is empty, 1 is returned.
+func MannWhitneyU(xs, ys []float64) float64 {
+	n1, n2 := len(xs), len(ys)
+	if n1 == 0 || n2 == 0 {
+		return 1
+	}
+
+	ranks, ties := rank(xs, ys)
+	var r1 float64
+	for _, r := range ranks[:n1] {
+		r1 += r
+	}
+	u := r1 - float64(n1*(n1+1))/2
+
+	if len(ties) == 0 && n1 <= maxExact && n2 <= maxExact {
+		return exactU(u, n1, n2)
+	}
+	return normalU(u, n1, n2, ties)
+}
+
+// rank returns the ranks of the combined samples, with ties given their average rank, along with
+// the size of each group of ties.
+func rank(xs, ys []float64) ([]float64, []int) {
+	type value struct {
+		v   float64
+		idx int
+	}
+	all := make([]value, 0, len(xs)+len(ys))
+	for _, v := range append(slices.Clone(xs), ys...) {
+		all = append(all, value{v: v, idx: len(all)})
+	}
+	slices.SortFunc(all, func(a, b value) int {
+		switch {
+		case a.v < b.v:
+			return -1
+		case a.v > b.v:
+			return 1
+		}
+		return 0
+	})
+
+	ranks := make([]float64, len(all))
+	var ties []int
+	for i := 0; i < len(all); {
+		j := i
+		for j < len(all) && all[j].v == all[i].v {
+			j++
+		}
+		avg := float64(i+j+1) / 2 // average of ranks i+1..j
+		for _, v := range all[i:j] {
+			ranks[v.idx] = avg
+		}
+		if j-i > 1 {
+			ties = append(ties, j-i)
+		}
+		i = j
+	}
+	return ranks, ties
+}
+
+// exactU returns the two-sided p-value of u using the exact distribution of U, which only holds
+// when there are no ties.
+func exactU(u float64, n1, n2 int) float64 {
+	// counts[n][u] is the number of arrangements with U = u, built up one sample size at a time.
+	counts := uCounts(n1, n2)
+	var total, below, above float64
+	for i, c := range counts {
+		total += c
+		if float64(i) <= u {
+			below += c
+		}
+		if float64(i) >= u {
+			above += c
+		}
+	}
+	return math.Min(1, 2*math.Min(below, above)/total)
+}
+
+// uCounts returns the number of ways each value of U can occur for samples of size n1 and n2.
+func uCounts(n1, n2 int) []float64 {
+	// prev[j][u] holds counts for sizes (i-1, j), cur[j][u] for sizes (i, j), using
+	// f(i, j, u) = f(i-1, j, u-j) + f(i, j-1, u).
+	maxU := n1 * n2
+	prev := make([][]float64, n2+1)
+	for j := range prev {
+		prev[j] = make([]float64, maxU+1)
+		prev[j][0] = 1
+	}
+	for i := 1; i <= n1; i++ {
+		cur := make([][]float64, n2+1)
+		for j := range cur {
+			cur[j] = make([]float64, maxU+1)
+			for u := 0; u <= maxU; u++ {
+				if j == 0 {
+					cur[j][u] = prev[j][u]
+					continue
+				}
+				if u >= j {
+					cur[j][u] += prev[j][u-j]
+				}
+				cur[j][u] += cur[j-1][u]
+			}
+		}
+		prev = cur
+	}
+	return prev[n2]
+}
+
+// normalU returns the two-sided p-value of u using a normal approximation, corrected for ties.
+func normalU(u float64, n1, n2 int, ties []int) float64 {
+	n := float64(n1 + n2)
+	var tieSum float64
+	for _, t := range ties {
+		tf := float64(t)
+		tieSum += tf*tf*tf - tf
+	}
+	mu := float64(n1*n2) / 2
+	variance := float64(n1*n2) / 12 * ((n + 1) - tieSum/(n*(n-1)))
+	if variance <= 0 {
+		return 1
+	}
+
+	diff := math.Abs(u-mu) - 0.5 // continuity correction
+	if diff < 0 {
+		diff = 0
+	}
+	z := diff / math.Sqrt(variance)
+	return math.Min(1, math.Erfc(z/math.Sqrt2))
+}
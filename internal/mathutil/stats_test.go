@@ -0,0 +1,89 @@
+package mathutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMeanMedian(t *testing.T) {
+	tests := []struct {
+		name       string
+		have       []float64
+		wantMean   float64
+		wantMedian float64
+	}{
+		{name: "empty", have: nil},
+		{name: "odd", have: []float64{3, 1, 2}, wantMean: 2, wantMedian: 2},
+		{name: "even", have: []float64{4, 1, 3, 10}, wantMean: 4.5, wantMedian: 3.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.wantMean, Mean(tt.have), 1e-9)
+			assert.InDelta(t, tt.wantMedian, Median(tt.have), 1e-9)
+		})
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	vals := []float64{15, 20, 35, 40, 50}
+	tests := []struct {
+		p    float64
+		want float64
+	}{
+		{p: 0, want: 15}, {p: 5, want: 15}, {p: 30, want: 20}, {p: 40, want: 20},
+		{p: 50, want: 35}, {p: 90, want: 50}, {p: 100, want: 50},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Percentile(vals, tt.p), "p%v", tt.p)
+	}
+	assert.Zero(t, Percentile(nil, 50))
+}
+
+func TestMedianCI(t *testing.T) {
+	tests := []struct {
+		name     string
+		have     []float64
+		wantLow  float64
+		wantHigh float64
+		wantConf float64
+	}{
+		{name: "empty", have: nil},
+		{name: "too few for 95%", have: []float64{2, 1, 3}, wantLow: 1, wantHigh: 3, wantConf: 0.75},
+		{
+			name:    "ten samples",
+			have:    []float64{10, 9, 8, 7, 6, 5, 4, 3, 2, 1},
+			wantLow: 2, wantHigh: 9, wantConf: 1 - 2*11.0/1024,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			low, high, conf := MedianCI(tt.have, 0.95)
+			assert.InDelta(t, tt.wantLow, low, 1e-9)
+			assert.InDelta(t, tt.wantHigh, high, 1e-9)
+			assert.InDelta(t, tt.wantConf, conf, 1e-9)
+		})
+	}
+}
+
+func TestMannWhitneyU(t *testing.T) {
+	tests := []struct {
+		name string
+		xs   []float64
+		ys   []float64
+		want float64
+	}{
+		{name: "empty", xs: nil, ys: []float64{1}, want: 1},
+		{name: "separated, exact", xs: []float64{1, 2, 3}, ys: []float64{4, 5, 6}, want: 0.1},
+		{name: "separated, reversed", xs: []float64{4, 5, 6, 7, 8}, ys: []float64{1, 2, 3, 0, -1}, want: 2.0 / 252},
+		{name: "interleaved, exact", xs: []float64{1, 3, 5}, ys: []float64{2, 4, 6}, want: 0.7},
+		{name: "all tied", xs: []float64{1, 1, 1}, ys: []float64{1, 1, 1}, want: 1},
+		// U = 0.5, mu = 8, sigma = sqrt(16/12 * (9 - 18/56)), z = (7.5 - 0.5) / sigma
+		{name: "ties, normal", xs: []float64{1, 2, 2, 3}, ys: []float64{3, 4, 4, 5}, want: 0.0396},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, MannWhitneyU(tt.xs, tt.ys), 1e-3)
+		})
+	}
+}
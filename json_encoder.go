@@ -0,0 +1,117 @@
+package tstat
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/nickfiggins/tstat/internal/gotest"
+)
+
+// JSONEncoder writes test runs back out as `go test -json` output, so runs that have been filtered or
+// redacted can be read by other tools that understand the test2json format.
+type JSONEncoder struct {
+	w          io.Writer
+	keepPkg    func(PackageRun) bool
+	keepTest   func(*Test) bool
+	redactLine func(string) string
+}
+
+// JSONOpt is a functional option for configuring a JSONEncoder.
+type JSONOpt func(*JSONEncoder)
+
+// WithPackageFilter only writes the packages that keep returns true for.
+func WithPackageFilter(keep func(PackageRun) bool) JSONOpt {
+	return func(e *JSONEncoder) {
+		e.keepPkg = keep
+	}
+}
+
+// WithTestFilter only writes the tests that keep returns true for. If a test isn't kept, neither are its
+// subtests.
+func WithTestFilter(keep func(*Test) bool) JSONOpt {
+	return func(e *JSONEncoder) {
+		e.keepTest = keep
+	}
+}
+
+// WithOutputRedactor replaces each line of output with the result of redact, e.g. to remove secrets or
+// paths from logs. Lines include their trailing newline. A line that was written in pieces, such as a
+// benchmark's name before its results, is redacted one piece at a time.
+func WithOutputRedactor(redact func(line string) string) JSONOpt {
+	return func(e *JSONEncoder) {
+		e.redactLine = redact
+	}
+}
+
+// NewJSONEncoder returns a new JSONEncoder that writes to w.
+func NewJSONEncoder(w io.Writer, opts ...JSONOpt) *JSONEncoder {
+	e := &JSONEncoder{w: w}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// Encode writes the events the test run was read from, with their original timestamps and output. Events from
+// packages that ran at the same time are interleaved by time, and the events of each package are kept in the
+// order they were written.
+func (e *JSONEncoder) Encode(run TestRun) error {
+	var pkgEvents [][]gotest.Event
+	for _, pkg := range run.pkgs {
+		if e.keepPkg != nil && !e.keepPkg(pkg) {
+			continue
+		}
+		pkgEvents = append(pkgEvents, e.packageEvents(pkg))
+	}
+
+	for _, event := range gotest.MergeByTime(pkgEvents...) {
+		b, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("couldn't marshal event: %w", err)
+		}
+		if _, err := e.w.Write(append(b, '\n')); err != nil {
+			return fmt.Errorf("couldn't write event: %w", err)
+		}
+	}
+	return nil
+}
+
+// packageEvents returns the events of the package that should be written, with their output redacted.
+func (e *JSONEncoder) packageEvents(pkg PackageRun) []gotest.Event {
+	tests := make(map[string]bool)
+	e.keptTests(pkg.Tests, tests)
+
+	events := make([]gotest.Event, 0, len(pkg.events))
+	for _, event := range pkg.events {
+		if event.Test != "" && !tests[event.Test] {
+			continue
+		}
+		if e.redactLine != nil && (event.Action == gotest.Out || event.Action == gotest.BuildOutput) {
+			event.Output = e.redact(event.Output)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// redact redacts each line of the output, which may have more than one line.
+func (e *JSONEncoder) redact(output string) string {
+	var sb strings.Builder
+	for _, line := range splitLines(output) {
+		sb.WriteString(e.redactLine(line))
+	}
+	return sb.String()
+}
+
+// keptTests adds the full names of the tests that should be written to kept.
+func (e *JSONEncoder) keptTests(tests []*Test, kept map[string]bool) {
+	for _, test := range tests {
+		if e.keepTest != nil && !e.keepTest(test) {
+			continue
+		}
+		kept[test.FullName] = true
+		e.keptTests(test.Subtests, kept)
+	}
+}
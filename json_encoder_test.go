@@ -0,0 +1,178 @@
+package tstat_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/nickfiggins/tstat"
+	"github.com/stretchr/testify/assert"
+)
+
+// linesByPackage groups the lines of test output JSON by package, keeping their order.
+func linesByPackage(t *testing.T, b []byte) map[string][]string {
+	t.Helper()
+	lines := make(map[string][]string)
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	sc.Buffer(nil, 1024*1024)
+	for sc.Scan() {
+		if strings.TrimSpace(sc.Text()) == "" {
+			continue
+		}
+		var e struct{ Package, ImportPath string }
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			t.Fatal(err)
+		}
+		pkg := e.Package
+		if pkg == "" {
+			pkg, _, _ = strings.Cut(e.ImportPath, " ")
+		}
+		lines[pkg] = append(lines[pkg], sc.Text())
+	}
+	return lines
+}
+
+func TestJSONEncoder_Encode(t *testing.T) {
+	files := []string{"testdata/bigtest.json", "testdata/failures.json", "testdata/skips.json", "testdata/07-02-2023-panic.json",
+		"testdata/depbuild.json"}
+	for _, file := range files {
+		t.Run(file, func(t *testing.T) {
+			b, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			run, err := tstat.TestsFromReader(bytes.NewReader(b))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var buf bytes.Buffer
+			if err := tstat.NewJSONEncoder(&buf).Encode(run); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, linesByPackage(t, b), linesByPackage(t, buf.Bytes()))
+			// packages that ran at the same time are interleaved the way they were originally.
+			assert.Equal(t, strings.TrimSpace(string(b)), strings.TrimSpace(buf.String()))
+		})
+	}
+}
+
+func TestJSONEncoder_Encode_Filters(t *testing.T) {
+	run, err := tstat.Tests("testdata/failures.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	enc := tstat.NewJSONEncoder(&buf,
+		tstat.WithPackageFilter(func(pkg tstat.PackageRun) bool { return pkg.Failed() }),
+		tstat.WithTestFilter(func(test *tstat.Test) bool { return test.FullName != "TestHang" }),
+		tstat.WithOutputRedactor(func(line string) string { return strings.ReplaceAll(line, "/tmp/bf", "REDACTED") }),
+	)
+	if err := enc.Encode(run); err != nil {
+		t.Fatal(err)
+	}
+	assert.NotContains(t, buf.String(), "/tmp/bf")
+	assert.Contains(t, buf.String(), "REDACTED/initpanic/init_test.go:5")
+
+	got, err := tstat.TestsFromReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, got.Packages(), 4)
+	_, ok := got.Package("example.com/bf/ok")
+	assert.False(t, ok, "passing package should be dropped")
+
+	timeout, ok := got.Package("example.com/bf/timeout")
+	if !ok {
+		t.Fatal("timeout package not found")
+	}
+	assert.Equal(t, 1, timeout.Count())
+	_, ok = timeout.Test("TestHang/sub")
+	assert.False(t, ok, "subtests of dropped tests should be dropped")
+}
+
+func TestJSONEncoder_Encode_Redact(t *testing.T) {
+	out := `{"Time":"2023-07-02T10:00:00Z","Action":"start","Package":"pkg"}
+{"Time":"2023-07-02T10:00:00Z","Action":"run","Package":"pkg","Test":"TestA"}
+{"Time":"2023-07-02T10:00:00Z","Action":"attr","Package":"pkg","Test":"TestA","Key":"owner","Value":"team"}
+{"Time":"2023-07-02T10:00:00Z","Action":"output","Package":"pkg","Test":"TestA","Output":"    a_test.go:5: token=abc\n    a_test.go:6: token=def\n"}
+{"Time":"2023-07-02T10:00:00Z","Action":"pass","Package":"pkg","Test":"TestA","Elapsed":0}
+{"Time":"2023-07-02T10:00:00Z","Action":"pass","Package":"pkg","Elapsed":0}`
+	run, err := tstat.TestsFromReader(strings.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var lines []string
+	var buf bytes.Buffer
+	enc := tstat.NewJSONEncoder(&buf, tstat.WithOutputRedactor(func(line string) string {
+		lines = append(lines, line)
+		if i := strings.Index(line, "token="); i != -1 {
+			return line[:i] + "token=REDACTED\n"
+		}
+		return line
+	}))
+	if err := enc.Encode(run); err != nil {
+		t.Fatal(err)
+	}
+	// the output event has two lines, which are redacted separately.
+	assert.Equal(t, []string{"    a_test.go:5: token=abc\n", "    a_test.go:6: token=def\n"}, lines)
+	assert.Contains(t, buf.String(), `"Output":"    a_test.go:5: token=REDACTED\n    a_test.go:6: token=REDACTED\n"`)
+	// the fields of actions that aren't known are kept.
+	assert.Contains(t, buf.String(), `{"Time":"2023-07-02T10:00:00Z","Action":"attr","Package":"pkg","Test":"TestA","Key":"owner","Value":"team"}`)
+}
+
+func TestJSONEncoder_Encode_Merged(t *testing.T) {
+	shard1 := `{"Time":"2023-07-02T10:00:00Z","Action":"start","Package":"pkg"}
+{"Time":"2023-07-02T10:00:00Z","Action":"run","Package":"pkg","Test":"TestA"}
+{"Time":"2023-07-02T10:00:01Z","Action":"fail","Package":"pkg","Test":"TestA","Elapsed":1}
+{"Time":"2023-07-02T10:00:01Z","Action":"output","Package":"pkg","Output":"FAIL\n"}
+{"Time":"2023-07-02T10:00:01Z","Action":"fail","Package":"pkg","Elapsed":1}
+`
+	shard2 := `{"Time":"2023-07-02T10:00:00Z","Action":"start","Package":"pkg"}
+{"Time":"2023-07-02T10:00:00Z","Action":"start","Package":"other"}
+{"Time":"2023-07-02T10:00:00Z","Action":"run","Package":"pkg","Test":"TestB"}
+{"Time":"2023-07-02T10:00:02Z","Action":"pass","Package":"pkg","Test":"TestB","Elapsed":2}
+{"Time":"2023-07-02T10:00:02Z","Action":"output","Package":"pkg","Output":"PASS\n"}
+{"Time":"2023-07-02T10:00:02Z","Action":"pass","Package":"pkg","Elapsed":2}
+{"Time":"2023-07-02T10:00:03Z","Action":"pass","Package":"other","Elapsed":3}
+`
+	runs := make([]tstat.TestRun, 0, 2)
+	for _, shard := range []string{shard1, shard2} {
+		run, err := tstat.TestsFromReader(strings.NewReader(shard))
+		if err != nil {
+			t.Fatal(err)
+		}
+		runs = append(runs, run)
+	}
+	merged := tstat.MergeTestRuns(runs...)
+
+	var buf bytes.Buffer
+	if err := tstat.NewJSONEncoder(&buf).Encode(merged); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	assert.Equal(t, 2, strings.Count(out, `"Action":"start"`), "one start event per package")
+	assert.Equal(t, 1, strings.Count(out, `"Action":"fail","Package":"pkg","Elapsed"`), "one final event for pkg")
+	assert.NotContains(t, out, `"Action":"pass","Package":"pkg","Elapsed"`)
+	assert.Contains(t, out, `{"Time":"2023-07-02T10:00:02Z","Action":"fail","Package":"pkg","Elapsed":2}`)
+
+	got, err := tstat.TestsFromReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, ok := got.Package("pkg")
+	if !ok {
+		t.Fatal("package not found")
+	}
+	want, _ := merged.Package("pkg")
+	assert.True(t, pkg.Failed())
+	assert.Equal(t, want.Duration(), pkg.Duration())
+	assert.Equal(t, 2, pkg.Count())
+	assert.Equal(t, merged.Duration(), got.Duration())
+	assert.Equal(t, merged.Summary(), got.Summary())
+}
@@ -0,0 +1,199 @@
+package tstat
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/nickfiggins/tstat/internal/junit"
+)
+
+// SubtestMode controls how subtests are represented in a JUnit report, which has no notion of nested tests.
+type SubtestMode int
+
+const (
+	// SubtestsFlatten writes every test and subtest as a separate test case, named by its full name.
+	SubtestsFlatten SubtestMode = iota
+	// SubtestsLeaves only writes tests that don't have subtests, so each test case is counted once.
+	SubtestsLeaves
+	// SubtestsTopLevel only writes top-level tests. Failures in subtests are reported on the top-level test.
+	SubtestsTopLevel
+)
+
+// incompleteMessage is the error message of test cases for tests that didn't finish.
+const incompleteMessage = "test didn't finish"
+
+// JUnitEncoder writes test runs as JUnit XML, with a test suite for each package.
+type JUnitEncoder struct {
+	w        io.Writer
+	subtests SubtestMode
+}
+
+// JUnitOpt is a functional option for configuring a JUnitEncoder.
+type JUnitOpt func(*JUnitEncoder)
+
+// WithSubtests sets how subtests are written. By default, they're flattened into separate test cases.
+func WithSubtests(mode SubtestMode) JUnitOpt {
+	return func(e *JUnitEncoder) {
+		e.subtests = mode
+	}
+}
+
+// NewJUnitEncoder returns a new JUnitEncoder that writes to w.
+func NewJUnitEncoder(w io.Writer, opts ...JUnitOpt) *JUnitEncoder {
+	e := &JUnitEncoder{w: w}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// Encode writes the test run as a JUnit XML report.
+func (e *JUnitEncoder) Encode(run TestRun) error {
+	report := junit.Testsuites{Time: seconds(run.Duration())}
+	for i, pkg := range run.pkgs {
+		suite := e.testsuite(pkg)
+		suite.ID = i
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Errors += suite.Errors
+		report.Skipped += suite.Skipped
+		report.Suites = append(report.Suites, suite)
+	}
+
+	if _, err := io.WriteString(e.w, xml.Header); err != nil {
+		return fmt.Errorf("couldn't write junit: %w", err)
+	}
+	enc := xml.NewEncoder(e.w)
+	enc.Indent("", "\t")
+	if err := enc.Encode(report); err != nil {
+		return fmt.Errorf("couldn't write junit: %w", err)
+	}
+	_, err := io.WriteString(e.w, "\n")
+	return err
+}
+
+func (e *JUnitEncoder) testsuite(pkg PackageRun) junit.Testsuite {
+	suite := junit.Testsuite{
+		Name: pkg.pkgName,
+		Time: seconds(pkg.Duration()),
+	}
+	if !pkg.start.IsZero() {
+		suite.Timestamp = pkg.start.Format(time.RFC3339Nano)
+	}
+	if pkg.Seed != 0 {
+		suite.Properties = append(suite.Properties, junit.Property{Name: "go.test.shuffle", Value: fmt.Sprint(pkg.Seed)})
+	}
+	if out := outputText(pkg.output); out != "" {
+		suite.SystemOut = &junit.Output{Data: sanitizeControlChars(out)}
+	}
+
+	for _, test := range e.tests(pkg.Tests) {
+		suite.Testcases = append(suite.Testcases, e.testcase(pkg.pkgName, test))
+	}
+
+	// failures outside of any test are reported as an extra test case, so they show up as errors.
+	switch reason := pkg.FailureReason(); reason { //nolint:exhaustive // other reasons are reported on tests
+	case FailureBuild, FailurePanic, FailureTestMain:
+		out := pkg.BuildOutput()
+		if reason != FailureBuild {
+			out = outputText(pkg.output)
+		}
+		suite.Testcases = append(suite.Testcases, junit.Testcase{
+			Name:      fmt.Sprintf("[%v failed]", reason),
+			Classname: pkg.pkgName,
+			Error:     &junit.Result{Message: fmt.Sprintf("package failed: %v", reason), Data: sanitizeControlChars(out)},
+		})
+	}
+
+	for _, tc := range suite.Testcases {
+		suite.Tests++
+		switch {
+		case tc.Failure != nil:
+			suite.Failures++
+		case tc.Error != nil:
+			suite.Errors++
+		case tc.Skipped != nil:
+			suite.Skipped++
+		}
+	}
+	return suite
+}
+
+// tests returns the tests to write as test cases, depending on how subtests are represented.
+func (e *JUnitEncoder) tests(tests []*Test) []*Test {
+	switch e.subtests {
+	case SubtestsTopLevel:
+		return tests
+	case SubtestsLeaves:
+		var leaves []*Test
+		for _, test := range flatten(tests) {
+			if len(test.Subtests) == 0 {
+				leaves = append(leaves, test)
+			}
+		}
+		return leaves
+	case SubtestsFlatten:
+	}
+	return flatten(tests)
+}
+
+func (e *JUnitEncoder) testcase(pkg string, test *Test) junit.Testcase {
+	tc := junit.Testcase{
+		Name:      test.FullName,
+		Classname: pkg,
+		Time:      seconds(test.Duration()),
+	}
+
+	out := outputText(test.output)
+	if e.subtests == SubtestsTopLevel {
+		for _, sub := range flatten(test.Subtests) {
+			out += outputText(sub.output)
+		}
+	}
+	out = sanitizeControlChars(out)
+
+	switch test.Status() {
+	case StatusFailed:
+		tc.Failure = &junit.Result{Message: failureMessage(test), Data: out}
+	case StatusIncomplete:
+		tc.Error = &junit.Result{Message: incompleteMessage, Data: out}
+	case StatusSkipped:
+		tc.Skipped = &junit.Result{Message: sanitizeControlChars(test.SkipReason()), Data: out}
+	case StatusPassed:
+		if out != "" {
+			tc.SystemOut = &junit.Output{Data: out}
+		}
+	}
+	return tc
+}
+
+// failureMessage returns a short description of why the test failed.
+func failureMessage(test *Test) string {
+	switch {
+	case test.TimedOut():
+		return "Timed out"
+	case test.FailureMessage() != "":
+		msg, _, _ := strings.Cut(test.FailureMessage(), "\n")
+		return sanitizeControlChars(msg)
+	}
+	if p, ok := test.Panic(); ok {
+		return sanitizeControlChars("Panic: " + p.Message)
+	}
+	return "Failed"
+}
+
+func outputText(output []OutputLine) string {
+	var sb strings.Builder
+	for _, line := range output {
+		sb.WriteString(line.Text)
+	}
+	return sb.String()
+}
+
+// seconds formats the duration as seconds, which is how durations are written in JUnit reports.
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,346 @@
+package tstat
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nickfiggins/tstat/internal/gotest"
+	"github.com/nickfiggins/tstat/internal/junit"
+)
+
+// WithJUnitInput configures the parser to read JUnit XML reports, such as those written by JUnitEncoder or
+// go-junit-report, instead of test output JSON. Each test suite is read as a package, and subtests are nested
+// under their parents based on their "/"-separated names. Parents that aren't in the report are added, and
+// get their status from their subtests like placeholders, see Test.Placeholder. Test cases with the same name
+// are read as attempts of the test, e.g. when it ran with -count, see Test.Attempts.
+//
+// JUnit reports only record when each suite started and how long each test took, so tests are assumed to
+// have run one after another from the start of their suite.
+func WithJUnitInput() TestOpt {
+	return func(tp *TestParser) {
+		tp.testParser = readJUnit
+	}
+}
+
+// readJUnit reads a JUnit XML report and converts it to the events `go test -json` would have written.
+func readJUnit(r io.Reader) ([]*gotest.PackageEvents, error) {
+	suites, err := junit.Read(r)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse junit: %w", err)
+	}
+
+	var events []gotest.Event
+	for _, suite := range suites {
+		events = append(events, junitSuiteEvents(suite)...)
+	}
+	return gotest.ByPackage(events), nil
+}
+
+// packageFailure matches the names of test cases that report a package failure rather than a test, e.g.
+// "[build failed]".
+var packageFailure = regexp.MustCompile(`^\[(\w+) failed\]$`) //nolint:gochecknoglobals // compiled once
+
+func junitSuiteEvents(suite junit.Testsuite) []gotest.Event {
+	pkg := suite.Name
+	if pkg == "" && len(suite.Testcases) > 0 {
+		pkg = suite.Testcases[0].Classname
+	}
+	if pkg == "" {
+		return nil
+	}
+
+	start := junitTimestamp(suite.Timestamp)
+	events := []gotest.Event{{Time: start, Action: gotest.Start, Package: pkg}}
+	for _, prop := range suite.Properties {
+		if prop.Name == "go.test.shuffle" {
+			events = append(events, gotest.Event{Time: start, Action: gotest.Out, Package: pkg,
+				Output: fmt.Sprintf("-test.shuffle %v\n", prop.Value)})
+		}
+	}
+
+	var testcases, pkgFailures []junit.Testcase
+	for _, tc := range suite.Testcases {
+		if packageFailure.MatchString(tc.Name) {
+			pkgFailures = append(pkgFailures, tc)
+			continue
+		}
+		testcases = append(testcases, tc)
+	}
+	tree := newJUnitTree(testcases)
+
+	end := start
+	failed := false
+	for _, node := range tree.roots {
+		events = append(events, node.events(pkg, end)...)
+		end = end.Add(node.duration())
+		// tests only don't finish when the test binary crashed, so the package didn't pass either.
+		status := node.status()
+		failed = failed || status == StatusFailed || status == StatusIncomplete
+	}
+
+	if suite.SystemOut != nil {
+		events = append(events, outputEvents(end, pkg, "", suite.SystemOut.Data)...)
+	}
+
+	final := gotest.Event{Action: gotest.Pass, Package: pkg}
+	for _, tc := range pkgFailures {
+		failed = true
+		if tc.Error == nil {
+			continue
+		}
+		switch packageFailure.FindStringSubmatch(tc.Name)[1] {
+		case "build":
+			for _, line := range splitLines(tc.Error.Data) {
+				events = append(events, gotest.Event{Time: end, Action: gotest.BuildOutput, Package: pkg, Output: line})
+			}
+			final.FailedBuild = pkg
+		default:
+			// the package's output is usually written to the suite, but fall back to the test case's.
+			if suite.SystemOut == nil {
+				events = append(events, outputEvents(end, pkg, "", tc.Error.Data)...)
+			}
+		}
+	}
+
+	if d, ok := junitDuration(suite.Time); ok && start.Add(d).After(end) {
+		end = start.Add(d)
+	}
+	if failed {
+		final.Action = gotest.Fail
+	}
+	final.Time = end
+	final.Elapsed = end.Sub(start).Seconds()
+	return append(events, final)
+}
+
+// junitTree nests test cases under their parents, based on their names.
+type junitTree struct {
+	roots []*junitNode
+	nodes map[string]*junitNode
+	cases map[string][]junit.Testcase
+}
+
+// junitNode is a test, which may not have any test cases if it was only inferred from its subtests' names. A
+// test with more than one test case ran more than once, e.g. with -count, and each case is an attempt.
+type junitNode struct {
+	name string
+	tcs  []junit.Testcase
+	subs []*junitNode
+}
+
+func newJUnitTree(testcases []junit.Testcase) *junitTree {
+	jt := &junitTree{nodes: make(map[string]*junitNode), cases: make(map[string][]junit.Testcase)}
+	for _, tc := range testcases {
+		jt.cases[tc.Name] = append(jt.cases[tc.Name], tc)
+	}
+	for _, tc := range testcases {
+		jt.node(tc.Name)
+	}
+	return jt
+}
+
+// node returns the test with the given name, adding it and any missing parents if it hasn't been seen yet.
+func (jt *junitTree) node(name string) *junitNode {
+	if node, ok := jt.nodes[name]; ok {
+		return node
+	}
+
+	node := &junitNode{name: name, tcs: jt.cases[name]}
+	jt.nodes[name] = node
+	if name := jt.parent(name); name != "" {
+		parent := jt.node(name)
+		parent.subs = append(parent.subs, node)
+	} else {
+		jt.roots = append(jt.roots, node)
+	}
+	return node
+}
+
+// parent returns the name of the test's parent. Subtest names can contain "/" too, so the closest ancestor
+// with a test case is used if there is one, otherwise the parent is inferred from the last "/".
+func (jt *junitTree) parent(name string) string {
+	for i := strings.LastIndex(name, testDelim); i > 0; i = strings.LastIndex(name[:i], testDelim) {
+		if _, ok := jt.cases[name[:i]]; ok {
+			return name[:i]
+		}
+	}
+	if i := strings.LastIndex(name, testDelim); i > 0 {
+		return name[:i]
+	}
+	return ""
+}
+
+// attempts returns how many times the test ran. A test inferred from its subtests ran as many times as the
+// subtest that ran the most.
+func (n *junitNode) attempts() int {
+	if len(n.tcs) > 0 {
+		return len(n.tcs)
+	}
+	attempts := 0
+	for _, sub := range n.subs {
+		attempts = max(attempts, sub.attempts())
+	}
+	return attempts
+}
+
+// duration returns the duration of every attempt at the test, which ran one after another.
+func (n *junitNode) duration() time.Duration {
+	var total time.Duration
+	for i := 0; i < n.attempts(); i++ {
+		total += n.attemptDuration(i)
+	}
+	return total
+}
+
+// attemptDuration returns the duration of the test case for the attempt, or the total of its subtests'
+// attempts if it doesn't have one.
+func (n *junitNode) attemptDuration(i int) time.Duration {
+	if i < len(n.tcs) {
+		if d, ok := junitDuration(n.tcs[i].Time); ok {
+			return d
+		}
+	}
+	var total time.Duration
+	for _, sub := range n.subs {
+		if i < sub.attempts() {
+			total += sub.attemptDuration(i)
+		}
+	}
+	return total
+}
+
+// status returns the outcome of the test, which failed if any of its attempts failed.
+func (n *junitNode) status() Status {
+	statuses := make([]Status, n.attempts())
+	for i := range statuses {
+		statuses[i] = n.attemptStatus(i)
+	}
+	return combinedStatus(statuses)
+}
+
+// attemptStatus returns the outcome of the attempt. Tests inferred from their subtests get their status from
+// them, like placeholders do, see Test.Placeholder.
+func (n *junitNode) attemptStatus(i int) Status {
+	if i < len(n.tcs) {
+		tc := n.tcs[i]
+		switch {
+		case tc.Failure != nil:
+			return StatusFailed
+		case tc.Error != nil && tc.Error.Message == incompleteMessage:
+			return StatusIncomplete
+		case tc.Error != nil:
+			return StatusFailed
+		case tc.Skipped != nil:
+			return StatusSkipped
+		}
+		return StatusPassed
+	}
+
+	var statuses []Status
+	for _, sub := range n.subs {
+		if i < sub.attempts() {
+			statuses = append(statuses, sub.attemptStatus(i))
+		}
+	}
+	return combinedStatus(statuses)
+}
+
+// events returns the events for every attempt at the test and its subtests, which run one after another from
+// start.
+func (n *junitNode) events(pkg string, start time.Time) []gotest.Event {
+	var events []gotest.Event
+	for i := 0; i < n.attempts(); i++ {
+		events = append(events, n.attemptEvents(pkg, i, start)...)
+		start = start.Add(n.attemptDuration(i))
+	}
+	return events
+}
+
+func (n *junitNode) attemptEvents(pkg string, i int, start time.Time) []gotest.Event {
+	events := []gotest.Event{{Time: start, Action: gotest.Run, Package: pkg, Test: n.name}}
+	if i < len(n.tcs) {
+		events = append(events, outputEvents(start, pkg, n.name, junitOutput(n.tcs[i]))...)
+	}
+
+	next := start
+	for _, sub := range n.subs {
+		if i < sub.attempts() {
+			events = append(events, sub.attemptEvents(pkg, i, next)...)
+			next = next.Add(sub.attemptDuration(i))
+		}
+	}
+
+	end := start.Add(n.attemptDuration(i))
+	final := gotest.Event{Time: end, Package: pkg, Test: n.name, Elapsed: end.Sub(start).Seconds()}
+	switch n.attemptStatus(i) {
+	case StatusFailed:
+		final.Action = gotest.Fail
+	case StatusSkipped:
+		final.Action = gotest.Skip
+	case StatusPassed:
+		final.Action = gotest.Pass
+	case StatusIncomplete:
+		return events
+	}
+	return append(events, final)
+}
+
+// junitOutput returns the output written during the test case. If the report only has a message describing
+// why the test failed or was skipped, the message is used instead.
+func junitOutput(tc junit.Testcase) string {
+	var sb strings.Builder
+	if tc.SystemOut != nil {
+		sb.WriteString(tc.SystemOut.Data)
+	}
+	for _, res := range []*junit.Result{tc.Failure, tc.Error, tc.Skipped} {
+		switch {
+		case res == nil:
+		case res.Data != "":
+			sb.WriteString(res.Data)
+		case res.Message != "":
+			sb.WriteString(res.Message + "\n")
+		}
+	}
+	return sb.String()
+}
+
+func outputEvents(at time.Time, pkg, test, output string) []gotest.Event {
+	var events []gotest.Event
+	for _, line := range splitLines(output) {
+		events = append(events, gotest.Event{Time: at, Action: gotest.Out, Package: pkg, Test: test, Output: line})
+	}
+	return events
+}
+
+// splitLines splits text into lines, keeping the trailing newline of each line.
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// junitTimestamp parses the time a test suite started. Reports without a timestamp are treated as starting
+// at the Unix epoch, so durations can still be calculated.
+func junitTimestamp(ts string) time.Time {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05"} {
+		if t, err := time.Parse(layout, ts); err == nil {
+			return t
+		}
+	}
+	return time.Unix(0, 0).UTC()
+}
+
+// junitDuration parses a duration written in seconds.
+func junitDuration(s string) (time.Duration, bool) {
+	secs, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || secs < 0 {
+		return 0, false
+	}
+	return time.Duration(secs * float64(time.Second)), true
+}
@@ -0,0 +1,186 @@
+package tstat_test
+
+import (
+	"bytes"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nickfiggins/tstat"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithJUnitInput(t *testing.T) {
+	f, err := os.Open("testdata/junit/report.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	run, err := tstat.NewTestParser(tstat.WithJUnitInput()).Stats(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkg, ok := run.Package("example.com/shop/cart")
+	if !ok {
+		t.Fatal("package not found")
+	}
+	assert.True(t, pkg.Failed())
+	assert.Equal(t, tstat.FailureTests, pkg.FailureReason())
+	assert.Equal(t, 730*time.Millisecond, pkg.Duration())
+	assert.Len(t, pkg.Tests, 3)
+	assert.Equal(t, 7, pkg.Count())
+
+	cart, ok := pkg.Test("TestCart")
+	if !ok {
+		t.Fatal("TestCart not found")
+	}
+	assert.Equal(t, tstat.StatusFailed, cart.Status())
+	assert.Equal(t, 500*time.Millisecond, cart.Duration())
+	remove, _ := cart.Test("remove")
+	assert.Equal(t, "cart_test.go:41: got 2 items, want 1", remove.FailureMessage())
+
+	checkout, ok := pkg.Test("TestCheckout")
+	if !ok {
+		t.Fatal("TestCheckout not found")
+	}
+	assert.Equal(t, tstat.StatusSkipped, checkout.Status())
+	payment, ok := checkout.Test("payment")
+	if !ok {
+		t.Fatal("TestCheckout/payment not found")
+	}
+	card, ok := payment.Test("card")
+	if !ok {
+		t.Fatal("TestCheckout/payment/card not found")
+	}
+	assert.Equal(t, "TestCheckout/payment/card", card.FullName)
+	assert.Equal(t, tstat.StatusSkipped, card.Status())
+	// the skip message doesn't have a file and line, so it's used as is.
+	assert.Equal(t, "requires stripe key", card.SkipReason())
+
+	total, _ := pkg.Test("TestTotal")
+	assert.Equal(t, tstat.StatusPassed, total.Status())
+	assert.Empty(t, pkg.Concurrent(total), "tests should run one after another")
+}
+
+func TestWithJUnitInput_Repeated(t *testing.T) {
+	// TestFlaky ran with -count=3, and only failed the second time.
+	report := `<testsuite name="pkg" tests="4" failures="1" time="6" timestamp="2023-07-02T14:05:10">
+	<testcase name="TestFlaky" classname="pkg" time="1"></testcase>
+	<testcase name="TestFlaky" classname="pkg" time="2">
+		<failure message="Failed"><![CDATA[    flaky_test.go:5: unlucky
+]]></failure>
+	</testcase>
+	<testcase name="TestFlaky" classname="pkg" time="3"></testcase>
+</testsuite>`
+	run, err := tstat.NewTestParser(tstat.WithJUnitInput()).Stats(strings.NewReader(report))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, _ := run.Package("pkg")
+	assert.True(t, pkg.Failed())
+	flaky, ok := pkg.Test("TestFlaky")
+	if !ok {
+		t.Fatal("TestFlaky not found")
+	}
+	assert.Equal(t, tstat.StatusFailed, flaky.Status())
+	assert.Equal(t, tstat.StabilityFlaky, flaky.Stability())
+	assert.Equal(t, "flaky_test.go:5: unlucky", flaky.FailureMessage())
+	assert.Equal(t, 6*time.Second, flaky.Duration())
+
+	attempts := flaky.Attempts()
+	if assert.Len(t, attempts, 3) {
+		for i, want := range []tstat.Status{tstat.StatusPassed, tstat.StatusFailed, tstat.StatusPassed} {
+			assert.Equal(t, want, attempts[i].Status)
+			assert.Equal(t, time.Duration(i+1)*time.Second, attempts[i].Duration())
+		}
+	}
+}
+
+func TestWithJUnitInput_IncompleteParent(t *testing.T) {
+	// TestParent isn't in the report, and none of its subtests finished.
+	report := `<testsuite name="pkg" tests="2" errors="2" time="1" timestamp="2023-07-02T14:05:10">
+	<testcase name="TestParent/a" classname="pkg" time="0.5">
+		<error message="test didn't finish"></error>
+	</testcase>
+	<testcase name="TestParent/b" classname="pkg" time="0.5">
+		<error message="test didn't finish"></error>
+	</testcase>
+</testsuite>`
+	run, err := tstat.NewTestParser(tstat.WithJUnitInput()).Stats(strings.NewReader(report))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, _ := run.Package("pkg")
+	parent, ok := pkg.Test("TestParent")
+	if !ok {
+		t.Fatal("TestParent not found")
+	}
+	assert.Equal(t, tstat.StatusIncomplete, parent.Status())
+	assert.Equal(t, 3, pkg.CountStatus(tstat.StatusIncomplete))
+	assert.True(t, pkg.Failed())
+}
+
+func TestWithJUnitInput_RoundTrip(t *testing.T) {
+	files := []string{"testdata/bigtest.json", "testdata/failures.json", "testdata/skips.json", "testdata/07-02-2023-panic.json"}
+	for _, file := range files {
+		t.Run(file, func(t *testing.T) {
+			want, err := tstat.Tests(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var buf bytes.Buffer
+			if err := tstat.NewJUnitEncoder(&buf).Encode(want); err != nil {
+				t.Fatal(err)
+			}
+			got, err := tstat.NewTestParser(tstat.WithJUnitInput()).Stats(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, want.Count(), got.Count())
+			assert.Equal(t, want.Failed(), got.Failed())
+			assert.Len(t, got.Packages(), len(want.Packages()))
+			for _, status := range []tstat.Status{tstat.StatusPassed, tstat.StatusFailed, tstat.StatusSkipped, tstat.StatusIncomplete} {
+				assert.Equal(t, want.CountStatus(status), got.CountStatus(status), status.String())
+			}
+			assert.Equal(t, skipReasons(want), skipReasons(got))
+
+			for _, wantPkg := range want.Packages() {
+				gotPkg, ok := got.Package(wantPkg.Name())
+				if !ok {
+					t.Errorf("package %v not found", wantPkg.Name())
+					continue
+				}
+				assert.Equal(t, wantPkg.FailureReason(), gotPkg.FailureReason(), wantPkg.Name())
+				assert.Equal(t, wantPkg.Seed, gotPkg.Seed)
+				assert.InDelta(t, wantPkg.Duration().Seconds(), gotPkg.Duration().Seconds(), 0.001)
+				for _, wantTest := range wantPkg.Tests {
+					gotTest, ok := gotPkg.Test(wantTest.Name)
+					if !ok {
+						t.Errorf("test %v not found", wantTest.FullName)
+						continue
+					}
+					assert.Equal(t, wantTest.Count(), gotTest.Count(), wantTest.FullName)
+					assert.Equal(t, wantTest.FailureMessage(), gotTest.FailureMessage(), wantTest.FullName)
+					assert.InDelta(t, wantTest.Duration().Seconds(), gotTest.Duration().Seconds(), 0.001, wantTest.FullName)
+				}
+			}
+		})
+	}
+}
+
+func skipReasons(run tstat.TestRun) map[string][]string {
+	reasons := make(map[string][]string)
+	for reason, tests := range run.SkipsByReason() {
+		for _, test := range tests {
+			reasons[reason] = append(reasons[reason], test.FullName)
+		}
+		sort.Strings(reasons[reason])
+	}
+	return reasons
+}
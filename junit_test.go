@@ -0,0 +1,142 @@
+package tstat_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/nickfiggins/tstat"
+	"github.com/nickfiggins/tstat/internal/junit"
+	"github.com/stretchr/testify/assert"
+)
+
+func encodeJUnit(t *testing.T, run tstat.TestRun, opts ...tstat.JUnitOpt) junit.Testsuites {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := tstat.NewJUnitEncoder(&buf, opts...).Encode(run); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), xml.Header) {
+		t.Errorf("missing xml header")
+	}
+
+	var report junit.Testsuites
+	if err := xml.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("couldn't decode junit: %v\n%s", err, buf.String())
+	}
+	return report
+}
+
+func testcase(t *testing.T, suite junit.Testsuite, name string) junit.Testcase {
+	t.Helper()
+	for _, tc := range suite.Testcases {
+		if tc.Name == name {
+			return tc
+		}
+	}
+	t.Fatalf("test case %v not found", name)
+	return junit.Testcase{}
+}
+
+func TestJUnitEncoder_Encode(t *testing.T) {
+	run, err := tstat.Tests("testdata/bigtest.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		mode      tstat.SubtestMode
+		wantTests int
+	}{
+		{mode: tstat.SubtestsFlatten, wantTests: 50},
+		{mode: tstat.SubtestsLeaves, wantTests: 41},
+		{mode: tstat.SubtestsTopLevel, wantTests: 13},
+	}
+	for _, tt := range tests {
+		report := encodeJUnit(t, run, tstat.WithSubtests(tt.mode))
+		assert.Len(t, report.Suites, len(run.Packages()))
+		assert.Equal(t, tt.wantTests, report.Tests)
+		assert.Zero(t, report.Failures)
+		assert.Zero(t, report.Errors)
+		assert.Equal(t, "0.473", report.Time)
+
+		var total int
+		for _, suite := range report.Suites {
+			assert.Len(t, suite.Testcases, suite.Tests)
+			total += suite.Tests
+		}
+		assert.Equal(t, tt.wantTests, total)
+	}
+
+	report := encodeJUnit(t, run)
+	for _, suite := range report.Suites {
+		if suite.Name != "github.com/nickfiggins/tstat" {
+			continue
+		}
+		tc := testcase(t, suite, "Test_CoverageStats/happy")
+		assert.Equal(t, "github.com/nickfiggins/tstat", tc.Classname)
+		assert.Nil(t, tc.Failure)
+		assert.Contains(t, tc.SystemOut.Data, "--- PASS: Test_CoverageStats/happy")
+	}
+}
+
+func TestJUnitEncoder_Encode_Failures(t *testing.T) {
+	run, err := tstat.Tests("testdata/failures.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := encodeJUnit(t, run)
+
+	suites := make(map[string]junit.Testsuite)
+	for _, suite := range report.Suites {
+		suites[suite.Name] = suite
+	}
+
+	broken := suites["example.com/bf/broken"]
+	assert.Equal(t, 1, broken.Errors)
+	tc := testcase(t, broken, "[build failed]")
+	assert.Contains(t, tc.Error.Data, "cannot use \"nope\"")
+
+	mainFail := suites["example.com/bf/mainfail"]
+	tc = testcase(t, mainFail, "[testmain failed]")
+	assert.Contains(t, tc.Error.Data, "setup: database unavailable")
+
+	timeout := suites["example.com/bf/timeout"]
+	assert.Equal(t, 2, timeout.Failures)
+	assert.Equal(t, "Timed out", testcase(t, timeout, "TestHang/sub").Failure.Message)
+	assert.NotNil(t, testcase(t, timeout, "TestFast").SystemOut)
+
+	run, err = tstat.Tests("testdata/skips.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	skips := encodeJUnit(t, run).Suites[0]
+	assert.Equal(t, 4, skips.Skipped)
+	assert.Equal(t, "requires docker", testcase(t, skips, "TestDocker").Skipped.Message)
+
+	run, err = tstat.Tests("testdata/07-02-2023-panic.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, suite := range encodeJUnit(t, run, tstat.WithSubtests(tstat.SubtestsLeaves)).Suites {
+		if suite.Name != "github.com/nickfiggins/tstat" {
+			continue
+		}
+		tc := testcase(t, suite, "Test_CoverageStatsFromReaders/happy")
+		assert.Equal(t, "Panic: panicked", tc.Failure.Message)
+		assert.Contains(t, tc.Failure.Data, "goroutine 39 [running]")
+	}
+}
+
+func TestJUnitEncoder_Encode_SanitizesOutput(t *testing.T) {
+	out := `{"Action":"run","Package":"pkg","Test":"TestColor"}
+{"Action":"output","Package":"pkg","Test":"TestColor","Output":"\u001b[31mred\u001b[0m ]]> done\n"}
+{"Action":"pass","Package":"pkg","Test":"TestColor"}`
+	run, err := tstat.TestsFromReader(strings.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := encodeJUnit(t, run)
+	assert.Equal(t, "�[31mred�[0m ]]> done\n", testcase(t, report.Suites[0], "TestColor").SystemOut.Data)
+}
@@ -0,0 +1,134 @@
+package tstat
+
+import (
+	"cmp"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// duplicateSuffix matches the suffix the testing package adds to a subtest with the same name as an earlier
+// subtest of the same parent, e.g. "case#01" for the second subtest named "case".
+var duplicateSuffix = regexp.MustCompile(`#(\d{2,})$`) //nolint:gochecknoglobals // compiled once
+
+// SanitizeTestName returns the name the testing package reports for a test started with t.Run(name, ...).
+// Spaces are replaced with underscores, and unprintable characters are escaped as they would be in a Go string
+// literal, e.g. "my case" becomes "my_case". Slashes are kept, so a full path of names can be sanitized at once.
+func SanitizeTestName(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case unicode.IsSpace(r):
+			b.WriteByte('_')
+		case !strconv.IsPrint(r):
+			quoted := strconv.QuoteRune(r)
+			b.WriteString(quoted[1 : len(quoted)-1])
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// BaseName returns the name of the test without the "#NN" suffix the testing package adds when a parent
+// runs more than one subtest with the same name.
+func (t *Test) BaseName() string {
+	return trimDuplicateSuffix(t.Name)
+}
+
+// DuplicateIndex returns which instance of a repeated subtest name the test is, based on its "#NN" suffix.
+// The first subtest with a name is 0, the next is 1 ("name#01"), and so on. Tests without the suffix are 0.
+func (t *Test) DuplicateIndex() int {
+	return duplicateIndex(t.Name)
+}
+
+func duplicateIndex(name string) int {
+	m := duplicateSuffix.FindStringSubmatch(name)
+	if m == nil {
+		return 0
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// SubtestGroup is a logical subtest, made up of every subtest the parent ran with the same name.
+type SubtestGroup struct {
+	Name      string  // Name is the subtest name, without any "#NN" suffix.
+	Instances []*Test // Instances are the subtests with the name, ordered by their DuplicateIndex.
+}
+
+// SubtestGroups returns the test's direct subtests, grouped so that subtests that only differ by their "#NN"
+// suffix are in the same group. Groups are in the order their first subtest is listed in Subtests.
+func (t *Test) SubtestGroups() []SubtestGroup {
+	var groups []SubtestGroup
+	index := make(map[string]int)
+	for _, sub := range t.Subtests {
+		name := sub.BaseName()
+		i, ok := index[name]
+		if !ok {
+			index[name] = len(groups)
+			groups = append(groups, SubtestGroup{Name: name, Instances: []*Test{sub}})
+			continue
+		}
+		groups[i].Instances = append(groups[i].Instances, sub)
+	}
+	// sorting by name puts "name#100" before "name#11", so instances are ordered by their index instead.
+	for _, g := range groups {
+		slices.SortStableFunc(g.Instances, compareInstances)
+	}
+	return groups
+}
+
+// TestInstances returns every test in the package whose full name matches the given path once "#NN" suffixes
+// are removed from each level, e.g. "TestA/case" returns "TestA/case", "TestA/case#01" and so on. Like
+// PackageRun.Test, names are matched case-insensitively, and the path may use the names passed to t.Run, see
+// SanitizeTestName. Instances are ordered by the DuplicateIndex of each level of their name.
+func (pr *PackageRun) TestInstances(path string) []*Test {
+	want := logicalName(SanitizeTestName(path))
+	var instances []*Test
+	_ = pr.Walk(func(v Visit) error {
+		name := logicalName(v.Test.FullName)
+		if strings.EqualFold(name, want) {
+			instances = append(instances, v.Test)
+		}
+		if !hasPrefixFold(want, name+testDelim) {
+			return SkipSubtests
+		}
+		return nil
+	})
+	slices.SortStableFunc(instances, compareInstances)
+	return instances
+}
+
+// compareInstances orders instances of the same logical test by the duplicate index of each level of their
+// full names, starting with the top-level test.
+func compareInstances(a, b *Test) int {
+	as, bs := strings.Split(a.FullName, testDelim), strings.Split(b.FullName, testDelim)
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if c := cmp.Compare(duplicateIndex(as[i]), duplicateIndex(bs[i])); c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(as), len(bs))
+}
+
+// logicalName removes the "#NN" suffix from every level of a test's full name.
+func logicalName(fullName string) string {
+	names := strings.Split(fullName, testDelim)
+	for i, name := range names {
+		names[i] = trimDuplicateSuffix(name)
+	}
+	return strings.Join(names, testDelim)
+}
+
+func trimDuplicateSuffix(name string) string {
+	if loc := duplicateSuffix.FindStringIndex(name); loc != nil {
+		return name[:loc[0]]
+	}
+	return name
+}
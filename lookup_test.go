@@ -0,0 +1,149 @@
+package tstat_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/nickfiggins/tstat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitizeTestName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "TestA", want: "TestA"},
+		{name: "TestA/my case", want: "TestA/my_case"},
+		{name: "tab\there", want: "tab_here"},
+		{name: "bell\a", want: `bell\a`},
+		{name: "ünïcode", want: "ünïcode"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tstat.SanitizeTestName(tt.name))
+		})
+	}
+}
+
+func TestPackageRun_Test_Nested(t *testing.T) {
+	run, err := tstat.Tests("testdata/duplicates.json")
+	require.NoError(t, err)
+	pkg, ok := run.Package("example.com/dup")
+	require.True(t, ok)
+
+	tests := []struct {
+		name   string
+		want   string
+		wantOk bool
+	}{
+		{name: "TestTable", want: "TestTable", wantOk: true},
+		{name: "TestTable/my_case#01/leaf#01", want: "TestTable/my_case#01/leaf#01", wantOk: true},
+		{name: "testtable/MY_CASE#02/leaf", want: "TestTable/my_case#02/leaf", wantOk: true},
+		{name: "TestTable/my case/leaf", want: "TestTable/my_case/leaf", wantOk: true},
+		{name: "TestTable/my case#03", wantOk: false},
+		{name: "TestTable/other/missing", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := pkg.Test(tt.name)
+			require.Equal(t, tt.wantOk, ok)
+			if ok {
+				assert.Equal(t, tt.want, got.FullName)
+			}
+		})
+	}
+
+	parent, ok := pkg.Test("TestTable")
+	require.True(t, ok)
+	leaf, ok := parent.Test("TestTable/other/leaf#01")
+	require.True(t, ok)
+	assert.Equal(t, "leaf", leaf.BaseName())
+	assert.Equal(t, 1, leaf.DuplicateIndex())
+}
+
+func TestTest_SubtestGroups(t *testing.T) {
+	run, err := tstat.Tests("testdata/duplicates.json")
+	require.NoError(t, err)
+	pkg, ok := run.Package("example.com/dup")
+	require.True(t, ok)
+	parent, ok := pkg.Test("TestTable")
+	require.True(t, ok)
+
+	groups := parent.SubtestGroups()
+	require.Len(t, groups, 2)
+	assert.Equal(t, "my_case", groups[0].Name)
+	require.Len(t, groups[0].Instances, 3)
+	for i, sub := range groups[0].Instances {
+		assert.Equal(t, i, sub.DuplicateIndex())
+		assert.Equal(t, "my_case", sub.BaseName())
+	}
+	assert.Equal(t, "other", groups[1].Name)
+	assert.Len(t, groups[1].Instances, 1)
+}
+
+func TestPackageRun_TestInstances(t *testing.T) {
+	run, err := tstat.Tests("testdata/duplicates.json")
+	require.NoError(t, err)
+	pkg, ok := run.Package("example.com/dup")
+	require.True(t, ok)
+
+	fullNames := func(tests []*tstat.Test) []string {
+		names := []string{}
+		for _, test := range tests {
+			names = append(names, test.FullName)
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"TestTable/my_case", "TestTable/my_case#01", "TestTable/my_case#02"},
+		fullNames(pkg.TestInstances("TestTable/my case")))
+	assert.Equal(t, []string{"TestTable/other/leaf", "TestTable/other/leaf#01"},
+		fullNames(pkg.TestInstances("TestTable/other/leaf")))
+	assert.Len(t, pkg.TestInstances("TestTable/my_case/leaf"), 6)
+	assert.Equal(t, []string{"TestTable"}, fullNames(pkg.TestInstances("TestTable")))
+	assert.Empty(t, pkg.TestInstances("TestMissing"))
+
+	// names are matched case-insensitively, like PackageRun.Test.
+	assert.Equal(t, []string{"TestTable/other/leaf", "TestTable/other/leaf#01"},
+		fullNames(pkg.TestInstances("testtable/OTHER/leaf")))
+	_, ok = pkg.Test("testtable/OTHER/leaf")
+	assert.True(t, ok)
+}
+
+func TestPackageRun_TestInstances_ManyDuplicates(t *testing.T) {
+	var b strings.Builder
+	b.WriteString(`{"Time":"2023-07-02T10:00:00Z","Action":"run","Package":"pkg","Test":"TestA"}` + "\n")
+	for i := 0; i <= 100; i++ {
+		name := "TestA/case"
+		if i > 0 {
+			name += fmt.Sprintf("#%02d", i)
+		}
+		fmt.Fprintf(&b, `{"Time":"2023-07-02T10:00:00Z","Action":"run","Package":"pkg","Test":%q}`+"\n", name)
+		fmt.Fprintf(&b, `{"Time":"2023-07-02T10:00:00Z","Action":"pass","Package":"pkg","Test":%q}`+"\n", name)
+	}
+	b.WriteString(`{"Time":"2023-07-02T10:00:00Z","Action":"pass","Package":"pkg","Test":"TestA"}` + "\n")
+	b.WriteString(`{"Time":"2023-07-02T10:00:00Z","Action":"pass","Package":"pkg"}`)
+	run, err := tstat.TestsFromReader(strings.NewReader(b.String()))
+	require.NoError(t, err)
+	pkg, ok := run.Package("pkg")
+	require.True(t, ok)
+	parent, ok := pkg.Test("TestA")
+	require.True(t, ok)
+
+	// sorted by name, "case#100" comes before "case#11".
+	groups := parent.SubtestGroups()
+	require.Len(t, groups, 1)
+	require.Len(t, groups[0].Instances, 101)
+	for i, sub := range groups[0].Instances {
+		assert.Equal(t, i, sub.DuplicateIndex())
+	}
+
+	instances := pkg.TestInstances("TestA/case")
+	require.Len(t, instances, 101)
+	for i, sub := range instances {
+		assert.Equal(t, i, sub.DuplicateIndex())
+	}
+}
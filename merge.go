@@ -0,0 +1,178 @@
+package tstat
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/nickfiggins/tstat/internal/gotest"
+)
+
+// MergeTestRuns combines multiple test runs into a single TestRun, e.g. when a test suite is sharded across
+// multiple machines that each write their own test output. The merged run starts when the earliest run
+// started and ends when the latest run ended.
+//
+// A package that appears in more than one run is merged into a single PackageRun, which spans all the runs it
+// appeared in and fails if any of them failed. Tests with the same name in a package are merged too, so a test
+// whose subtests were split across shards has all of its subtests. The runs passed in aren't modified.
+func MergeTestRuns(runs ...TestRun) TestRun {
+	var pkgs []PackageRun
+	byName := make(map[string]int)
+	for _, run := range runs {
+		for _, pkg := range run.pkgs {
+			i, ok := byName[pkg.pkgName]
+			if !ok {
+				byName[pkg.pkgName] = len(pkgs)
+				pkgs = append(pkgs, pkg)
+				continue
+			}
+			pkgs[i] = pkgs[i].merge(pkg)
+		}
+	}
+	slices.SortFunc(pkgs, comparePackageNames)
+
+	merged := TestRun{}
+	for _, pkg := range pkgs {
+		merged.addPackage(pkg)
+	}
+	return merged
+}
+
+// merge returns a new PackageRun combining the results of both runs of the package.
+func (pr PackageRun) merge(other PackageRun) PackageRun {
+	merged := PackageRun{
+		pkgName:     pr.pkgName,
+		start:       pr.start,
+		end:         pr.end,
+		Tests:       mergeTests(pr.Tests, other.Tests),
+		Benchmarks:  append(slices.Clip(pr.Benchmarks), other.Benchmarks...),
+		Seed:        pr.Seed,
+		failed:      pr.failed || other.failed,
+		output:      append(slices.Clip(pr.output), other.output...),
+		buildOutput: append(slices.Clip(pr.buildOutput), other.buildOutput...),
+		buildFailed: pr.buildFailed || other.buildFailed,
+		panicked:    pr.panicked || other.panicked,
+		warnings:    append(slices.Clip(pr.warnings), other.warnings...),
+
+		timeoutOutput: pr.timeoutOutput,
+	}
+	if isBefore(merged.start, other.start) {
+		merged.start = other.start
+	}
+	if isAfter(merged.end, other.end) {
+		merged.end = other.end
+	}
+	if merged.Seed == 0 {
+		merged.Seed = other.Seed
+	}
+	if len(merged.timeoutOutput) == 0 {
+		merged.timeoutOutput = other.timeoutOutput
+	}
+	merged.events = merged.mergeEvents(pr.events, other.events)
+	return merged
+}
+
+// mergeEvents merges the events of both runs of the package by time. Only the first start event and a single
+// final event for the package are kept, so the merged events can be read back as one run of the package.
+func (pr PackageRun) mergeEvents(events, others []gotest.Event) []gotest.Event {
+	merged := make([]gotest.Event, 0, len(events)+len(others))
+	var started bool
+	var final *gotest.Event
+	for _, e := range gotest.MergeByTime(events, others) {
+		switch {
+		case e.PackageEvent() && e.Action == gotest.Start:
+			if started {
+				continue
+			}
+			started = true
+		case e.PackageEvent() && e.Action.IsFinal():
+			if final == nil || final.Action != gotest.Fail {
+				e := e
+				final = &e
+			}
+			continue
+		}
+		merged = append(merged, e)
+	}
+
+	if final != nil {
+		end := *final
+		end.Time = pr.end
+		if !pr.start.IsZero() {
+			// test2json reports elapsed seconds to the millisecond.
+			end.Elapsed = math.Round(pr.end.Sub(pr.start).Seconds()*1000) / 1000
+		}
+		if pr.failed {
+			end.Action = gotest.Fail
+		}
+		merged = append(merged, end)
+	}
+	return merged
+}
+
+// mergeTests returns the tests from both lists, merging tests with the same name. The tests passed in
+// aren't modified.
+func mergeTests(tests, others []*Test) []*Test {
+	merged := slices.Clone(tests)
+	for _, other := range others {
+		i := slices.IndexFunc(merged, func(test *Test) bool { return test.FullName == other.FullName })
+		if i == -1 {
+			merged = append(merged, other)
+			continue
+		}
+		merged[i] = merged[i].merge(other)
+	}
+	slices.SortFunc(merged, CompareTestNames)
+	return merged
+}
+
+// mergeAttempts returns the attempts from both runs of a test, keeping track of which run each came from
+// since the runs may have happened at the same time.
+func mergeAttempts(attempts, others []Attempt) []Attempt {
+	next := 0
+	for _, a := range attempts {
+		next = max(next, a.shard+1)
+	}
+	merged := slices.Clone(attempts)
+	for _, a := range others {
+		a.shard += next
+		merged = append(merged, a)
+	}
+	return merged
+}
+
+// merge returns a new Test combining the results of both runs of the test.
+func (t *Test) merge(other *Test) *Test {
+	merged := &Test{
+		Subtests:  mergeTests(t.Subtests, other.Subtests),
+		actions:   append(slices.Clip(t.actions), other.actions...),
+		FullName:  t.FullName,
+		Name:      t.Name,
+		Package:   t.Package,
+		output:    append(slices.Clip(t.output), other.output...),
+		attempts:  mergeAttempts(t.attempts, other.attempts),
+		pauses:    append(slices.Clip(t.pauses), other.pauses...),
+		timedOut:  t.timedOut || other.timedOut,
+		panicked:  t.panicked || other.panicked,
+		pkgPassed: t.pkgPassed && other.pkgPassed,
+		// a test is only a placeholder if it had no events of its own in either run.
+		placeholder: t.placeholder && other.placeholder,
+		start:       t.start,
+		end:         t.end,
+	}
+	switch {
+	case merged.placeholder:
+		merged.start, merged.end = time.Time{}, time.Time{}
+		spanPlaceholders([]*Test{merged})
+	case t.placeholder:
+		merged.start, merged.end = other.start, other.end
+	case !other.placeholder:
+		if isBefore(merged.start, other.start) {
+			merged.start = other.start
+		}
+		if isAfter(merged.end, other.end) {
+			merged.end = other.end
+		}
+	}
+	return merged
+}
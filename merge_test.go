@@ -0,0 +1,176 @@
+package tstat_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nickfiggins/tstat"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeTestRuns(t *testing.T) {
+	shard1 := `{"Time":"2023-07-02T10:00:00Z","Action":"start","Package":"a"}
+{"Time":"2023-07-02T10:00:01Z","Action":"run","Package":"a","Test":"TestA"}
+{"Time":"2023-07-02T10:00:01Z","Action":"run","Package":"a","Test":"TestA/x"}
+{"Time":"2023-07-02T10:00:02Z","Action":"pass","Package":"a","Test":"TestA/x","Elapsed":1}
+{"Time":"2023-07-02T10:00:02Z","Action":"pass","Package":"a","Test":"TestA","Elapsed":1}
+{"Time":"2023-07-02T10:00:03Z","Action":"pass","Package":"a","Elapsed":3}
+{"Time":"2023-07-02T10:00:00Z","Action":"start","Package":"b"}
+{"Time":"2023-07-02T10:00:01Z","Action":"run","Package":"b","Test":"TestB"}
+{"Time":"2023-07-02T10:00:02Z","Action":"pass","Package":"b","Test":"TestB","Elapsed":1}
+{"Time":"2023-07-02T10:00:02Z","Action":"pass","Package":"b","Elapsed":2}`
+	shard2 := `{"Time":"2023-07-02T09:59:59Z","Action":"start","Package":"a"}
+{"Time":"2023-07-02T10:00:00Z","Action":"run","Package":"a","Test":"TestA"}
+{"Time":"2023-07-02T10:00:00Z","Action":"run","Package":"a","Test":"TestA/y"}
+{"Time":"2023-07-02T10:00:04Z","Action":"fail","Package":"a","Test":"TestA/y","Elapsed":4}
+{"Time":"2023-07-02T10:00:04Z","Action":"fail","Package":"a","Test":"TestA","Elapsed":4}
+{"Time":"2023-07-02T10:00:04Z","Action":"run","Package":"a","Test":"TestC"}
+{"Time":"2023-07-02T10:00:05Z","Action":"pass","Package":"a","Test":"TestC","Elapsed":1}
+{"Time":"2023-07-02T10:00:06Z","Action":"fail","Package":"a","Elapsed":7}`
+
+	run1, err := tstat.TestsFromReader(strings.NewReader(shard1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	run2, err := tstat.TestsFromReader(strings.NewReader(shard2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	merged := tstat.MergeTestRuns(run1, run2)
+	assert.Len(t, merged.Packages(), 2)
+	assert.Equal(t, 5, merged.Count())
+	assert.True(t, merged.Failed())
+	assert.Equal(t, 7*time.Second, merged.Duration())
+
+	pkg, ok := merged.Package("a")
+	if !ok {
+		t.Fatal("package a not found")
+	}
+	assert.True(t, pkg.Failed())
+	assert.Equal(t, 7*time.Second, pkg.Duration())
+	assert.Len(t, pkg.Tests, 2)
+
+	testA, ok := pkg.Test("TestA")
+	if !ok {
+		t.Fatal("TestA not found")
+	}
+	assert.Len(t, testA.Subtests, 2)
+	assert.True(t, testA.Failed())
+	assert.Equal(t, 4*time.Second, testA.Duration())
+	assert.Equal(t, 4*time.Second, testA.WallTime())
+
+	// the shards are left as they were.
+	pkg1, _ := run1.Package("a")
+	assert.False(t, pkg1.Failed())
+	assert.Equal(t, 2, pkg1.Count())
+	assert.Equal(t, 3*time.Second, run1.Duration())
+}
+
+func TestMergeTestRuns_SameRun(t *testing.T) {
+	run, err := tstat.Tests("testdata/bigtest.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	merged := tstat.MergeTestRuns(run, run)
+	assert.Len(t, merged.Packages(), len(run.Packages()))
+	assert.Equal(t, run.Count(), merged.Count())
+	assert.Equal(t, run.Duration(), merged.Duration())
+	assert.Equal(t, run.Failed(), merged.Failed())
+
+	assert.Equal(t, tstat.TestRun{}, tstat.MergeTestRuns())
+}
+
+func TestMergeTestRuns_Elapsed(t *testing.T) {
+	// shard1 ran TestA twice with -count=2, one after the other, while shard2 ran it once.
+	shard1 := `{"Time":"2023-07-02T10:00:00Z","Action":"start","Package":"a"}
+{"Time":"2023-07-02T10:00:00Z","Action":"run","Package":"a","Test":"TestA"}
+{"Time":"2023-07-02T10:00:02Z","Action":"pass","Package":"a","Test":"TestA","Elapsed":2}
+{"Time":"2023-07-02T10:00:02Z","Action":"run","Package":"a","Test":"TestA"}
+{"Time":"2023-07-02T10:00:04Z","Action":"pass","Package":"a","Test":"TestA","Elapsed":2}
+{"Time":"2023-07-02T10:00:04Z","Action":"pass","Package":"a","Elapsed":4}`
+	shard2 := `{"Time":"2023-07-02T10:00:00Z","Action":"start","Package":"a"}
+{"Time":"2023-07-02T10:00:00Z","Action":"run","Package":"a","Test":"TestA"}
+{"Time":"2023-07-02T10:00:03Z","Action":"pass","Package":"a","Test":"TestA","Elapsed":3}
+{"Time":"2023-07-02T10:00:03Z","Action":"pass","Package":"a","Elapsed":3}`
+
+	var runs []tstat.TestRun
+	for _, shard := range []string{shard1, shard2, shard2} {
+		run, err := tstat.TestsFromReader(strings.NewReader(shard))
+		if err != nil {
+			t.Fatal(err)
+		}
+		runs = append(runs, run)
+	}
+
+	merged := tstat.MergeTestRuns(runs...)
+	pkg, _ := merged.Package("a")
+	test, ok := pkg.Test("TestA")
+	if !ok {
+		t.Fatal("TestA not found")
+	}
+	elapsed, ok := test.Elapsed()
+	assert.True(t, ok)
+	assert.Equal(t, 4*time.Second, elapsed, "the attempts of shard1 add up to the longest shard")
+	assert.Len(t, test.Attempts(), 4)
+
+	merged = tstat.MergeTestRuns(runs[1:]...)
+	pkg, _ = merged.Package("a")
+	test, _ = pkg.Test("TestA")
+	assert.Equal(t, 3*time.Second, test.Duration())
+}
+
+func TestMergeTestRuns_Placeholders(t *testing.T) {
+	// neither shard has the events of TestP, so it's added as a placeholder for its subtests.
+	shard1 := `{"Time":"2023-07-02T10:00:00Z","Action":"start","Package":"a"}
+{"Time":"2023-07-02T10:00:00Z","Action":"run","Package":"a","Test":"TestP/x"}
+{"Time":"2023-07-02T10:00:01Z","Action":"pass","Package":"a","Test":"TestP/x","Elapsed":1}
+{"Time":"2023-07-02T10:00:01Z","Action":"pass","Package":"a","Elapsed":1}`
+	shard2 := `{"Time":"2023-07-02T10:00:00Z","Action":"start","Package":"a"}
+{"Time":"2023-07-02T10:00:02Z","Action":"run","Package":"a","Test":"TestP/y"}
+{"Time":"2023-07-02T10:00:05Z","Action":"pass","Package":"a","Test":"TestP/y","Elapsed":3}
+{"Time":"2023-07-02T10:00:05Z","Action":"pass","Package":"a","Elapsed":5}`
+	shard3 := `{"Time":"2023-07-02T10:00:00Z","Action":"start","Package":"a"}
+{"Time":"2023-07-02T10:00:03Z","Action":"run","Package":"a","Test":"TestP"}
+{"Time":"2023-07-02T10:00:04Z","Action":"pass","Package":"a","Test":"TestP","Elapsed":1}
+{"Time":"2023-07-02T10:00:04Z","Action":"pass","Package":"a","Elapsed":4}`
+
+	var runs []tstat.TestRun
+	for _, shard := range []string{shard1, shard2, shard3} {
+		run, err := tstat.NewTestParser(tstat.WithLenientParsing()).Stats(strings.NewReader(shard))
+		if err != nil {
+			t.Fatal(err)
+		}
+		runs = append(runs, run)
+	}
+	parent := func(run tstat.TestRun) *tstat.Test {
+		t.Helper()
+		pkg, _ := run.Package("a")
+		test, ok := pkg.Test("TestP")
+		if !ok {
+			t.Fatal("TestP not found")
+		}
+		return test
+	}
+
+	test := parent(tstat.MergeTestRuns(runs[0], runs[0]))
+	assert.True(t, test.Placeholder())
+	assert.Equal(t, tstat.StatusPassed, test.Status())
+	assert.Equal(t, time.Second, test.Duration())
+
+	test = parent(tstat.MergeTestRuns(runs[0], runs[1]))
+	assert.True(t, test.Placeholder())
+	assert.Equal(t, tstat.StatusPassed, test.Status())
+	assert.Equal(t, 5*time.Second, test.Duration(), "the placeholder spans the subtests of both runs")
+
+	// a test with events of its own isn't a placeholder once merged, and keeps its own timing.
+	for _, merged := range []tstat.TestRun{tstat.MergeTestRuns(runs...), tstat.MergeTestRuns(runs[2], runs[0])} {
+		test = parent(merged)
+		assert.False(t, test.Placeholder())
+		assert.Equal(t, tstat.StatusPassed, test.Status())
+		assert.Equal(t, time.Second, test.Duration())
+		assert.Equal(t, time.Second, test.WallTime())
+	}
+}
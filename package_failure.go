@@ -0,0 +1,157 @@
+package tstat
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nickfiggins/tstat/internal/gopanic"
+	"github.com/nickfiggins/tstat/internal/gotest"
+)
+
+// FailureReason describes why a package failed.
+type FailureReason int
+
+const (
+	// FailureNone means the package didn't fail.
+	FailureNone FailureReason = iota
+	// FailureTests means one or more tests in the package failed.
+	FailureTests
+	// FailureBuild means the package or its test binary failed to build, so no tests were run.
+	FailureBuild
+	// FailurePanic means the test binary panicked outside of any test, e.g. during package initialization.
+	FailurePanic
+	// FailureTestMain means the test binary exited with a failure without any test failing, which usually
+	// means TestMain failed during setup or called os.Exit.
+	FailureTestMain
+	// FailureTimeout means the test binary was stopped because it ran longer than the -timeout flag.
+	FailureTimeout
+)
+
+func (r FailureReason) String() string {
+	switch r {
+	case FailureNone:
+		return "none"
+	case FailureTests:
+		return "tests"
+	case FailureBuild:
+		return "build"
+	case FailurePanic:
+		return "panic"
+	case FailureTestMain:
+		return "testmain"
+	case FailureTimeout:
+		return "timeout"
+	}
+	return "unknown"
+}
+
+const (
+	panicPrefix   = "panic: "
+	timeoutPrefix = "panic: test timed out after"
+)
+
+// withOutput records the details of an event that describe how the package ran, outside of the results of
+// individual tests.
+func (pr *PackageRun) withOutput(e gotest.Event) {
+	switch {
+	case e.Action == gotest.BuildOutput:
+		pr.buildOutput = append(pr.buildOutput, e.Output)
+	case e.Action == gotest.BuildFail, e.FailedBuild != "":
+		pr.buildFailed = true
+	case e.Action == gotest.Out:
+		pr.withTimeoutOutput(e.Output)
+		if e.Test != "" {
+			return
+		}
+		pr.output = append(pr.output, OutputLine{Time: e.Time, Text: e.Output})
+		if strings.HasPrefix(e.Output, panicPrefix) {
+			pr.panicked = true
+		}
+		// before Go 1.24, build errors are only written to stderr, but the result is still reported.
+		if strings.Contains(e.Output, "[build failed]") || strings.Contains(e.Output, "[setup failed]") {
+			pr.buildFailed = true
+		}
+	}
+}
+
+// FailureReason returns the reason the package failed, or FailureNone if it didn't fail.
+func (pr *PackageRun) FailureReason() FailureReason {
+	switch {
+	case pr.buildFailed:
+		return FailureBuild
+	case pr.TimedOut():
+		return FailureTimeout
+	case pr.panicked:
+		return FailurePanic
+	case anyFailed(pr.Tests):
+		return FailureTests
+	case pr.failed:
+		return FailureTestMain
+	}
+	return FailureNone
+}
+
+// withTimeoutOutput collects the lines of a timeout panic, up to the end of the list of running tests.
+func (pr *PackageRun) withTimeoutOutput(output string) {
+	switch {
+	case strings.HasPrefix(output, timeoutPrefix):
+		pr.timeoutOutput = []string{output}
+	case len(pr.timeoutOutput) > 0 && strings.TrimSpace(pr.timeoutOutput[len(pr.timeoutOutput)-1]) != "":
+		pr.timeoutOutput = append(pr.timeoutOutput, output)
+	}
+}
+
+// TimedOut returns true if the package's test binary ran longer than the -timeout flag allowed.
+func (pr *PackageRun) TimedOut() bool {
+	return len(pr.timeoutOutput) > 0
+}
+
+// Timeout returns the timeout that was exceeded, or 0 if the package didn't time out.
+func (pr *PackageRun) Timeout() time.Duration {
+	timeout, _ := gopanic.ParseTimeout(pr.timeoutOutput)
+	return timeout.After
+}
+
+// HungTests returns the tests that were still running when the package timed out.
+func (pr *PackageRun) HungTests() []*Test {
+	var hung []*Test
+	for _, test := range flatten(pr.Tests) {
+		if test.timedOut {
+			hung = append(hung, test)
+		}
+	}
+	return hung
+}
+
+// markTimedOut marks the tests that were running when the package timed out.
+func (pr *PackageRun) markTimedOut() {
+	timeout, ok := gopanic.ParseTimeout(pr.timeoutOutput)
+	if !ok {
+		return
+	}
+	for _, test := range flatten(pr.Tests) {
+		test.timedOut = slices.Contains(timeout.Running, test.FullName)
+	}
+}
+
+func anyFailed(tests []*Test) bool {
+	for _, test := range tests {
+		if test.Failed() || anyFailed(test.Subtests) {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildOutput returns the output from building the package's test binary, such as compiler errors. It's only
+// recorded by Go 1.24 and later, earlier versions write it to stderr.
+func (pr *PackageRun) BuildOutput() string {
+	return strings.Join(pr.buildOutput, "")
+}
+
+// Output returns the output written by the package that wasn't part of any test, such as output from
+// TestMain or a panic during initialization.
+func (pr *PackageRun) Output() []OutputLine {
+	return pr.output
+}
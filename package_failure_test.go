@@ -0,0 +1,133 @@
+package tstat_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/nickfiggins/tstat"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageRun_FailureReason(t *testing.T) {
+	tests := []struct {
+		testFile, pkg string
+		want          tstat.FailureReason
+		wantFailed    bool
+	}{
+		{testFile: "testdata/failures.json", pkg: "example.com/bf/broken", want: tstat.FailureBuild, wantFailed: true},
+		{testFile: "testdata/failures.json", pkg: "example.com/bf/initpanic", want: tstat.FailurePanic, wantFailed: true},
+		{testFile: "testdata/failures.json", pkg: "example.com/bf/mainfail", want: tstat.FailureTestMain, wantFailed: true},
+		{testFile: "testdata/failures.json", pkg: "example.com/bf/timeout", want: tstat.FailureTimeout, wantFailed: true},
+		{testFile: "testdata/failures.json", pkg: "example.com/bf/ok", want: tstat.FailureNone},
+		{testFile: "testdata/07-02-2023-panic.json", pkg: "github.com/nickfiggins/tstat", want: tstat.FailureTests, wantFailed: true},
+		{testFile: "testdata/depbuild.json", pkg: "example.com/dep/a", want: tstat.FailureBuild, wantFailed: true},
+		{testFile: "testdata/depbuild.json", pkg: "example.com/dep/c", want: tstat.FailureNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pkg, func(t *testing.T) {
+			run, err := tstat.Tests(tt.testFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := os.Open(tt.testFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			stream := tstat.NewTestStream()
+			if err := stream.Consume(f); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, run := range []tstat.TestRun{run, stream.Run()} {
+				pkg, ok := run.Package(tt.pkg)
+				if !ok {
+					t.Fatalf("package %v not found", tt.pkg)
+				}
+				assert.Equal(t, tt.want, pkg.FailureReason(), "got %v, want %v", pkg.FailureReason(), tt.want)
+				assert.Equal(t, tt.wantFailed, pkg.Failed())
+			}
+		})
+	}
+}
+
+func TestPackageRun_BuildOutput(t *testing.T) {
+	run, err := tstat.Tests("testdata/failures.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	broken, _ := run.Package("example.com/bf/broken")
+	assert.Equal(t, "# example.com/bf/broken [example.com/bf/broken.test]\n"+
+		"broken/broken_test.go:6:14: cannot use \"nope\" (untyped string constant) as int value in variable declaration\n",
+		broken.BuildOutput())
+	assert.Zero(t, broken.Count())
+
+	mainFail, _ := run.Package("example.com/bf/mainfail")
+	assert.Empty(t, mainFail.BuildOutput())
+	if assert.NotEmpty(t, mainFail.Output()) {
+		assert.Equal(t, "setup: database unavailable\n", mainFail.Output()[0].Text)
+	}
+}
+
+func TestPackageRun_BuildOutput_Dependency(t *testing.T) {
+	run, err := tstat.Tests("testdata/depbuild.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open("testdata/depbuild.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	stream := tstat.NewTestStream()
+	if err := stream.Consume(f); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, run := range []tstat.TestRun{run, stream.Run()} {
+		// the dependency that failed to build isn't a package that was tested.
+		assert.Len(t, run.Packages(), 2)
+		_, ok := run.Package("example.com/dep/b")
+		assert.False(t, ok)
+
+		a, _ := run.Package("example.com/dep/a")
+		assert.Equal(t, "# example.com/dep/b\n"+
+			"b/b.go:3:13: cannot use \"nope\" (untyped string constant) as int value in variable declaration\n",
+			a.BuildOutput())
+		c, _ := run.Package("example.com/dep/c")
+		assert.Empty(t, c.BuildOutput())
+	}
+}
+
+func TestPackageRun_TimedOut(t *testing.T) {
+	run, err := tstat.Tests("testdata/failures.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkg, _ := run.Package("example.com/bf/timeout")
+	assert.True(t, pkg.TimedOut())
+	assert.Equal(t, time.Second, pkg.Timeout())
+
+	hung := pkg.HungTests()
+	names := make([]string, len(hung))
+	for i, test := range hung {
+		names[i] = test.FullName
+		assert.True(t, test.TimedOut())
+		assert.True(t, test.Failed())
+	}
+	assert.ElementsMatch(t, []string{"TestHang", "TestHang/sub"}, names)
+
+	fast, _ := pkg.Test("TestFast")
+	assert.False(t, fast.TimedOut())
+	assert.False(t, fast.Failed())
+
+	ok, _ := run.Package("example.com/bf/ok")
+	assert.False(t, ok.TimedOut())
+	assert.Zero(t, ok.Timeout())
+	assert.Empty(t, ok.HungTests())
+}
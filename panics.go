@@ -0,0 +1,106 @@
+package tstat
+
+import (
+	"strings"
+
+	"github.com/nickfiggins/tstat/internal/gopanic"
+)
+
+// Panic is a panic that occurred while running tests.
+type Panic struct {
+	Message   string       // Message is the value that was passed to panic.
+	Recovered bool         // Recovered is true if the panic was recovered, then panicked again.
+	Test      string       // Test is the full name of the test the panic is attributed to, if one could be found.
+	Goroutine int          // Goroutine is the ID of the goroutine that panicked.
+	Stack     []StackFrame // Stack is the stack trace of the goroutine that panicked, most recent call first.
+}
+
+// StackFrame is a single function call in a stack trace.
+type StackFrame struct {
+	Function  string // Function is the fully qualified name of the function, e.g. "pkg.(*Type).Method".
+	File      string // File is the full path of the file the function call is in.
+	Line      int    // Line is the line number of the function call.
+	CreatedBy bool   // CreatedBy is true if this is the call that started the goroutine.
+}
+
+// Panic returns the panic that occurred during the test, if any. Timeouts are reported as panics by the
+// testing package, but aren't included.
+func (t *Test) Panic() (*Panic, bool) {
+	p, ok := parsePanic(t.output)
+	if !ok {
+		return nil, false
+	}
+	p.Test = t.FullName
+	return p, true
+}
+
+// markPanicked marks the tests that panicked before they reached a final action. A test that finished is only
+// considered to have panicked if it failed, since it may have logged something that looks like a panic.
+func (pr *PackageRun) markPanicked() {
+	for _, test := range flatten(pr.Tests) {
+		test.panicked = false
+		if !test.finished() {
+			_, test.panicked = test.Panic()
+		}
+	}
+}
+
+// Panic returns the panic that occurred in the package outside of any test, such as during initialization
+// or in a goroutine started by a test that outlived it. If the panic's stack trace includes one of the
+// package's tests, it's attributed to that test.
+func (pr *PackageRun) Panic() (*Panic, bool) {
+	p, ok := parsePanic(pr.output)
+	if !ok {
+		return nil, false
+	}
+
+	for _, frame := range p.Stack {
+		if test, ok := pr.testInFrame(frame.Function); ok {
+			p.Test = test.FullName
+			break
+		}
+	}
+	return p, true
+}
+
+// testInFrame returns the top-level test the function belongs to, e.g. "pkg.TestAdd.func1" is part of TestAdd.
+func (pr *PackageRun) testInFrame(fn string) (*Test, bool) {
+	var rest string
+	for _, prefix := range []string{pr.pkgName + ".", pr.pkgName + "_test."} {
+		if strings.HasPrefix(fn, prefix) {
+			rest = strings.TrimPrefix(fn, prefix)
+		}
+	}
+	name, _, _ := strings.Cut(rest, ".")
+	if name == "" {
+		return nil, false
+	}
+	for _, test := range pr.Tests {
+		if test.FullName == name {
+			return test, true
+		}
+	}
+	return nil, false
+}
+
+func parsePanic(output []OutputLine) (*Panic, bool) {
+	lines := make([]string, len(output))
+	for i, line := range output {
+		lines[i] = line.Text
+	}
+	p, ok := gopanic.Parse(lines)
+	if !ok || strings.HasPrefix(panicPrefix+p.Message, timeoutPrefix) {
+		return nil, false
+	}
+
+	stack := make([]StackFrame, len(p.Frames))
+	for i, f := range p.Frames {
+		stack[i] = StackFrame{Function: f.Function, File: f.File, Line: f.Line, CreatedBy: f.CreatedBy}
+	}
+	return &Panic{
+		Message:   p.Message,
+		Recovered: p.Recovered,
+		Goroutine: p.Goroutine,
+		Stack:     stack,
+	}, true
+}
@@ -0,0 +1,135 @@
+package tstat_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nickfiggins/tstat"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTest_Panic(t *testing.T) {
+	run, err := tstat.Tests("testdata/07-02-2023-panic.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, _ := run.Package("github.com/nickfiggins/tstat")
+	parent, ok := pkg.Test("Test_CoverageStatsFromReaders")
+	if !ok {
+		t.Fatal("test not found")
+	}
+	test, ok := parent.Test("happy")
+	if !ok {
+		t.Fatal("subtest not found")
+	}
+
+	p, ok := test.Panic()
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, "panicked", p.Message)
+	assert.True(t, p.Recovered)
+	assert.Equal(t, "Test_CoverageStatsFromReaders/happy", p.Test)
+	assert.Equal(t, 39, p.Goroutine)
+	assert.Len(t, p.Stack, 7)
+	assert.Contains(t, p.Stack, tstat.StackFrame{
+		Function: "github.com/nickfiggins/tstat_test.Test_CoverageStatsFromReaders.func1",
+		File:     "/Users/nickfiggins/dev/tstat/tstat_test.go",
+		Line:     71,
+	})
+	assert.True(t, test.Failed())
+
+	_, ok = parent.Panic()
+	assert.False(t, ok)
+
+	_, ok = pkg.Panic()
+	assert.False(t, ok)
+}
+
+func TestTest_Panic_Timeout(t *testing.T) {
+	run, err := tstat.Tests("testdata/failures.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, _ := run.Package("example.com/bf/timeout")
+	for _, test := range pkg.Tests {
+		for _, sub := range append([]*tstat.Test{test}, test.Subtests...) {
+			_, ok := sub.Panic()
+			assert.False(t, ok, "timeouts shouldn't be reported as panics")
+		}
+	}
+}
+
+func TestPackageRun_Panic(t *testing.T) {
+	run, err := tstat.Tests("testdata/failures.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, _ := run.Package("example.com/bf/initpanic")
+	p, ok := pkg.Panic()
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, "bad config", p.Message)
+	assert.Empty(t, p.Test)
+	assert.Equal(t, []tstat.StackFrame{
+		{Function: "example.com/bf/initpanic.init.func1", File: "/tmp/bf/initpanic/init_test.go", Line: 5},
+		{Function: "example.com/bf/initpanic.init", File: "/tmp/bf/initpanic/init_test.go", Line: 5},
+	}, p.Stack)
+}
+
+func TestPackageRun_Panic_Attributed(t *testing.T) {
+	// the panic happens in a goroutine started by TestLeak after it finished, so it isn't attributed to it
+	// by test2json, and the test never finishes.
+	out := `{"Action":"start","Package":"pkg"}
+{"Action":"run","Package":"pkg","Test":"TestLeak"}
+{"Action":"run","Package":"pkg","Test":"TestLeak/sub"}
+{"Action":"fail","Package":"pkg","Test":"TestLeak/sub"}
+{"Action":"output","Package":"pkg","Output":"panic: leaked\n"}
+{"Action":"output","Package":"pkg","Output":"\n"}
+{"Action":"output","Package":"pkg","Output":"goroutine 7 [running]:\n"}
+{"Action":"output","Package":"pkg","Output":"pkg_test.TestLeak.func1()\n"}
+{"Action":"output","Package":"pkg","Output":"\t/src/pkg/leak_test.go:10 +0x25\n"}
+{"Action":"output","Package":"pkg","Output":"created by pkg_test.TestLeak in goroutine 6\n"}
+{"Action":"output","Package":"pkg","Output":"\t/src/pkg/leak_test.go:9 +0x25\n"}
+{"Action":"fail","Package":"pkg","Elapsed":0.1}`
+	run, err := tstat.TestsFromReader(strings.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, _ := run.Package("pkg")
+	p, ok := pkg.Panic()
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, "TestLeak", p.Test)
+	assert.Equal(t, tstat.FailurePanic, pkg.FailureReason())
+
+	test, _ := pkg.Test("TestLeak")
+	assert.True(t, test.Failed(), "unfinished parent of a failed subtest should fail")
+}
+
+func TestTest_Failed_LoggedPanic(t *testing.T) {
+	out := `{"Time":"2023-07-02T10:00:00Z","Action":"start","Package":"pkg"}
+{"Time":"2023-07-02T10:00:00Z","Action":"run","Package":"pkg","Test":"TestLogs"}
+{"Time":"2023-07-02T10:00:00Z","Action":"output","Package":"pkg","Test":"TestLogs","Output":"panic: not really\n"}
+{"Time":"2023-07-02T10:00:00Z","Action":"output","Package":"pkg","Test":"TestLogs","Output":"\n"}
+{"Time":"2023-07-02T10:00:00Z","Action":"output","Package":"pkg","Test":"TestLogs","Output":"goroutine 7 [running]:\n"}
+{"Time":"2023-07-02T10:00:01Z","Action":"pass","Package":"pkg","Test":"TestLogs","Elapsed":1}
+{"Time":"2023-07-02T10:00:01Z","Action":"pass","Package":"pkg","Elapsed":1}
+`
+	run, err := tstat.TestsFromReader(strings.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, _ := run.Package("pkg")
+	test, ok := pkg.Test("TestLogs")
+	if !ok {
+		t.Fatal("test not found")
+	}
+
+	_, logged := test.Panic()
+	assert.True(t, logged)
+	assert.False(t, test.Failed(), "a test that passed shouldn't fail because of its output")
+	assert.Equal(t, tstat.StatusPassed, test.Status())
+}
@@ -0,0 +1,142 @@
+package tstat
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Match is a test selected by a query, with the package it belongs to.
+type Match struct {
+	Package PackageRun // Package is the package the test belongs to.
+	Test    *Test      // Test is the matching test.
+	Depth   int        // Depth is how deeply the test is nested, where top-level tests are at depth 0.
+}
+
+// Filter decides whether a test should be selected.
+type Filter func(Match) bool
+
+// Select returns the tests in the package, including subtests, that match all of the filters. Tests are
+// returned in the order they're nested, with each test before its subtests.
+func (pr *PackageRun) Select(filters ...Filter) []Match {
+	var matches []Match
+	_ = pr.Walk(func(v Visit) error {
+		m := Match{Package: *pr, Test: v.Test, Depth: v.Depth}
+		if matchesAll(m, filters) {
+			matches = append(matches, m)
+		}
+		return nil
+	})
+	return matches
+}
+
+// Select returns the tests in all packages, including subtests, that match all of the filters.
+func (tr *TestRun) Select(filters ...Filter) []Match {
+	var matches []Match
+	for i := range tr.pkgs {
+		matches = append(matches, tr.pkgs[i].Select(filters...)...)
+	}
+	return matches
+}
+
+func matchesAll(m Match, filters []Filter) bool {
+	for _, f := range filters {
+		if !f(m) {
+			return false
+		}
+	}
+	return true
+}
+
+// HasStatus selects tests with any of the given statuses.
+func HasStatus(statuses ...Status) Filter {
+	return func(m Match) bool {
+		return slices.Contains(statuses, m.Test.Status())
+	}
+}
+
+// InPackage selects tests in packages matching the pattern, using the syntax of path.Match, e.g.
+// "github.com/org/repo/*". An error is returned if the pattern is malformed.
+func InPackage(pattern string) (Filter, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid package pattern %q: %w", pattern, err)
+	}
+	return func(m Match) bool {
+		ok, _ := path.Match(pattern, m.Package.pkgName)
+		return ok
+	}, nil
+}
+
+// MatchRun selects tests the same way `go test -run` does. The pattern is split by unbracketed slashes into
+// a regular expression for each level of subtest, and each level of the test's name must match the
+// corresponding expression. Like `go test -run`, parents of the tests that match are selected too, since
+// they have to run for their subtests to run. An error is returned if any expression is invalid.
+func MatchRun(pattern string) (Filter, error) {
+	var levels []*regexp.Regexp
+	for _, expr := range splitRunPattern(pattern) {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid run pattern %q: %w", pattern, err)
+		}
+		levels = append(levels, re)
+	}
+
+	return func(m Match) bool {
+		names := strings.Split(m.Test.FullName, testDelim)
+		for i, name := range names {
+			if i >= len(levels) {
+				break
+			}
+			if !levels[i].MatchString(name) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// splitRunPattern splits a -run pattern by the slashes that aren't in brackets or parentheses.
+func splitRunPattern(pattern string) []string {
+	var exprs []string
+	var depth, start int
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '[', '(':
+			depth++
+		case ']', ')':
+			depth--
+		case '\\':
+			i++ // skip the escaped character
+		case '/':
+			if depth == 0 {
+				exprs = append(exprs, pattern[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(exprs, pattern[start:])
+}
+
+// MinDuration selects tests that took at least d.
+func MinDuration(d time.Duration) Filter {
+	return func(m Match) bool {
+		return m.Test.Duration() >= d
+	}
+}
+
+// MaxDuration selects tests that took at most d.
+func MaxDuration(d time.Duration) Filter {
+	return func(m Match) bool {
+		return m.Test.Duration() <= d
+	}
+}
+
+// MaxDepth selects tests that are nested at most depth levels deep. A depth of 0 only selects top-level tests.
+func MaxDepth(depth int) Filter {
+	return func(m Match) bool {
+		return m.Depth <= depth
+	}
+}
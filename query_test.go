@@ -0,0 +1,134 @@
+package tstat_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nickfiggins/tstat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func matchNames(matches []tstat.Match) []string {
+	names := []string{}
+	for _, m := range matches {
+		names = append(names, m.Test.FullName)
+	}
+	return names
+}
+
+func TestTestRun_Select(t *testing.T) {
+	run, err := tstat.Tests("testdata/bigtest.json")
+	require.NoError(t, err)
+
+	byRun := func(pattern string) tstat.Filter {
+		f, err := tstat.MatchRun(pattern)
+		require.NoError(t, err)
+		return f
+	}
+	internal, err := tstat.InPackage("github.com/nickfiggins/tstat/internal/*")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		filters []tstat.Filter
+		want    []string
+	}{
+		{
+			name:    "run pattern per level",
+			filters: []tstat.Filter{byRun("TestAction_IsFinal/^(pass|fail)$")},
+			want:    []string{"TestAction_IsFinal", "TestAction_IsFinal/fail", "TestAction_IsFinal/pass"},
+		},
+		{
+			name:    "run pattern is unanchored",
+			filters: []tstat.Filter{byRun("Test_Coverage")},
+			want: []string{
+				"Test_CoverageStats", "Test_CoverageStats/file_not_found", "Test_CoverageStats/happy",
+				"Test_CoverageStatsFromReaders", "Test_CoverageStatsFromReaders/happy", "Test_CoverageStats_CmdError",
+			},
+		},
+		{
+			name:    "max depth",
+			filters: []tstat.Filter{byRun("Test_Coverage"), tstat.MaxDepth(0)},
+			want:    []string{"Test_CoverageStats", "Test_CoverageStatsFromReaders", "Test_CoverageStats_CmdError"},
+		},
+		{
+			name:    "package glob",
+			filters: []tstat.Filter{internal, byRun("^TestAction_/^pass$")},
+			want:    []string{"TestAction_IsFinal", "TestAction_IsFinal/pass", "TestAction_String", "TestAction_String/pass"},
+		},
+		{
+			name:    "status",
+			filters: []tstat.Filter{tstat.HasStatus(tstat.StatusFailed, tstat.StatusSkipped)},
+			want:    []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := matchNames(run.Select(tt.filters...))
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+
+	slow := run.Select(tstat.MinDuration(50*time.Millisecond), tstat.MaxDuration(time.Second))
+	require.NotEmpty(t, slow)
+	for _, m := range slow {
+		assert.GreaterOrEqual(t, m.Test.Duration(), 50*time.Millisecond)
+		assert.LessOrEqual(t, m.Test.Duration(), time.Second)
+	}
+
+	all := run.Select()
+	assert.Len(t, all, run.Count())
+	for _, m := range all {
+		assert.Equal(t, m.Test.Package, m.Package.Name())
+	}
+}
+
+func TestPackageRun_Select_Order(t *testing.T) {
+	run, err := tstat.Tests("testdata/bigtest.json")
+	require.NoError(t, err)
+	pkg, ok := run.Package("github.com/nickfiggins/tstat")
+	require.True(t, ok)
+
+	f, err := tstat.MatchRun("Test_Internal_TestRunFromReader")
+	require.NoError(t, err)
+	got := pkg.Select(f)
+	require.Len(t, got, 4)
+	assert.Equal(t, "Test_Internal_TestRunFromReader", got[0].Test.FullName)
+	assert.Equal(t, 0, got[0].Depth)
+	for _, m := range got[1:] {
+		assert.Equal(t, 1, m.Depth)
+	}
+}
+
+func TestMatchRun(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{pattern: "", name: "TestA/sub", want: true},
+		{pattern: "A", name: "TestA/sub", want: true},
+		{pattern: "^A", name: "TestA", want: false},
+		{pattern: "TestA/sub", name: "TestA", want: true},
+		{pattern: "TestA/sub", name: "TestA/other", want: false},
+		{pattern: "TestA/sub", name: "TestA/sub/deeper", want: true},
+		{pattern: "TestA/[/]", name: "TestA/x", want: false},
+		{pattern: "TestA/(a|b/c)", name: "TestA/a", want: true},
+		{pattern: `TestA/a\/b`, name: "TestA/a", want: false},
+		{pattern: "/sub$", name: "TestB/sub", want: true},
+		{pattern: "/sub$", name: "TestB/subtest", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.name, func(t *testing.T) {
+			f, err := tstat.MatchRun(tt.pattern)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, f(tstat.Match{Test: &tstat.Test{FullName: tt.name}}))
+		})
+	}
+
+	_, err := tstat.MatchRun("TestA/(")
+	assert.Error(t, err)
+	_, err = tstat.InPackage("[")
+	assert.Error(t, err)
+}
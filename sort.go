@@ -0,0 +1,42 @@
+package tstat
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// CompareTestNames orders tests by their full name, which is the order tests and subtests are in when
+// they're parsed. It can be used with SortTests, or with slices.SortFunc.
+func CompareTestNames(a, b *Test) int {
+	return strings.Compare(a.FullName, b.FullName)
+}
+
+// CompareTestDurations orders tests from the longest to the shortest duration, and then by name.
+func CompareTestDurations(a, b *Test) int {
+	if c := cmp.Compare(b.Duration(), a.Duration()); c != 0 {
+		return c
+	}
+	return CompareTestNames(a, b)
+}
+
+// SortTests sorts the tests, and the subtests of each test, in place. Tests that compare equal keep their
+// original order.
+func SortTests(tests []*Test, compare func(a, b *Test) int) {
+	slices.SortStableFunc(tests, compare)
+	for _, test := range tests {
+		SortTests(test.Subtests, compare)
+	}
+}
+
+// SortTests sorts the tests in each package, and their subtests, in place. See SortTests.
+func (tr *TestRun) SortTests(compare func(a, b *Test) int) {
+	for _, pkg := range tr.pkgs {
+		SortTests(pkg.Tests, compare)
+	}
+}
+
+// comparePackageNames orders package runs by their import path.
+func comparePackageNames(a, b PackageRun) int {
+	return strings.Compare(a.pkgName, b.pkgName)
+}
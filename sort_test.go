@@ -0,0 +1,95 @@
+package tstat_test
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/nickfiggins/tstat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoverFromReaders_Order(t *testing.T) {
+	coverage := func() tstat.Coverage {
+		cover, err := os.Open("testdata/go-cmp/cover.out")
+		require.NoError(t, err)
+		defer cover.Close()
+		fn, err := os.Open("testdata/go-cmp/func.out")
+		require.NoError(t, err)
+		defer fn.Close()
+
+		stats, err := tstat.CoverFromReaders(cover, fn)
+		require.NoError(t, err)
+		return stats
+	}
+
+	stats := coverage()
+	require.Len(t, stats.Packages, 5)
+	assert.True(t, slices.IsSortedFunc(stats.Packages, func(a, b *tstat.PackageCoverage) int {
+		return strings.Compare(a.Name, b.Name)
+	}))
+	for _, pkg := range stats.Packages {
+		assert.True(t, slices.IsSortedFunc(pkg.Files, func(a, b *tstat.FileCoverage) int {
+			return strings.Compare(a.Name, b.Name)
+		}), pkg.Name)
+		for _, f := range pkg.Files {
+			assert.NotEmpty(t, f.Functions, f.Name)
+		}
+	}
+	assert.Equal(t, stats, coverage())
+}
+
+func TestTests_Order(t *testing.T) {
+	names := func(run tstat.TestRun) []string {
+		var names []string
+		for _, pkg := range run.Packages() {
+			names = append(names, pkg.Name())
+			require.NoError(t, pkg.Walk(func(v tstat.Visit) error {
+				names = append(names, v.Test.FullName)
+				return nil
+			}))
+		}
+		return names
+	}
+
+	run, err := tstat.Tests("testdata/bigtest.json")
+	require.NoError(t, err)
+	got := names(run)
+	for i := 0; i < 5; i++ {
+		again, err := tstat.Tests("testdata/bigtest.json")
+		require.NoError(t, err)
+		require.Equal(t, got, names(again))
+	}
+
+	pkgs := run.Packages()
+	assert.True(t, slices.IsSortedFunc(pkgs, func(a, b tstat.PackageRun) int {
+		return strings.Compare(a.Name(), b.Name())
+	}))
+	for _, pkg := range pkgs {
+		assert.True(t, slices.IsSortedFunc(pkg.Tests, tstat.CompareTestNames), pkg.Name())
+	}
+}
+
+func TestSortTests(t *testing.T) {
+	run, err := tstat.Tests("testdata/bigtest.json")
+	require.NoError(t, err)
+
+	run.SortTests(tstat.CompareTestDurations)
+	for _, pkg := range run.Packages() {
+		assert.True(t, slices.IsSortedFunc(pkg.Tests, tstat.CompareTestDurations), pkg.Name())
+		require.NoError(t, pkg.Walk(func(v tstat.Visit) error {
+			assert.True(t, slices.IsSortedFunc(v.Test.Subtests, tstat.CompareTestDurations), v.Test.FullName)
+			return nil
+		}))
+	}
+
+	tests := []*tstat.Test{{FullName: "TestB"}, {FullName: "TestA", Subtests: []*tstat.Test{
+		{FullName: "TestA/z"}, {FullName: "TestA/y"},
+	}}}
+	tstat.SortTests(tests, tstat.CompareTestNames)
+	assert.Equal(t, "TestA", tests[0].FullName)
+	assert.Equal(t, "TestA/y", tests[0].Subtests[0].FullName)
+	assert.Equal(t, "TestB", tests[1].FullName)
+}
@@ -0,0 +1,133 @@
+package tstat
+
+import (
+	"slices"
+
+	"github.com/nickfiggins/tstat/internal/gotest"
+)
+
+// Status is the outcome of a test.
+type Status int
+
+const (
+	// StatusIncomplete means the test started but never finished, e.g. because the test binary crashed or
+	// the output was truncated.
+	StatusIncomplete Status = iota
+	// StatusPassed means the test passed.
+	StatusPassed
+	// StatusFailed means the test failed, panicked or timed out.
+	StatusFailed
+	// StatusSkipped means the test was skipped.
+	StatusSkipped
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusIncomplete:
+		return "incomplete"
+	case StatusPassed:
+		return "passed"
+	case StatusFailed:
+		return "failed"
+	case StatusSkipped:
+		return "skipped"
+	}
+	return "unknown"
+}
+
+// Status returns the outcome of the test, based on the actions recorded for it. Benchmarks don't report
+// a result, so they're considered passed if their package passed.
+func (t *Test) Status() Status {
+	switch {
+	case t.Failed():
+		return StatusFailed
+	case t.placeholder:
+		return placeholderStatus(t.Subtests)
+	case slices.Contains(t.actions, gotest.Skip):
+		return StatusSkipped
+	case slices.Contains(t.actions, gotest.Pass), slices.Contains(t.actions, gotest.Bench), t.pkgPassed:
+		return StatusPassed
+	}
+	return StatusIncomplete
+}
+
+// placeholderStatus returns the status of a placeholder test that didn't fail, based on its subtests, see
+// combinedStatus.
+func placeholderStatus(subtests []*Test) Status {
+	statuses := make([]Status, len(subtests))
+	for i, sub := range subtests {
+		statuses[i] = sub.Status()
+	}
+	return combinedStatus(statuses)
+}
+
+// combinedStatus returns the status of a test made up of the given statuses. It failed if any of them failed,
+// and is incomplete if any of them are, or if there are none. Otherwise it passed if any of them passed, and
+// was skipped if all of them were skipped.
+func combinedStatus(statuses []Status) Status {
+	if len(statuses) == 0 {
+		return StatusIncomplete
+	}
+	status := StatusSkipped
+	for _, s := range statuses {
+		switch s { //nolint:exhaustive // skipped doesn't change the status
+		case StatusFailed:
+			return StatusFailed
+		case StatusIncomplete:
+			status = StatusIncomplete
+		case StatusPassed:
+			if status != StatusIncomplete {
+				status = StatusPassed
+			}
+		}
+	}
+	return status
+}
+
+// CountStatus returns the number of tests with the given status, including subtests.
+func (pr *PackageRun) CountStatus(status Status) int {
+	var count int
+	for _, test := range flatten(pr.Tests) {
+		if test.Status() == status {
+			count++
+		}
+	}
+	return count
+}
+
+// CountStatus returns the number of tests with the given status, including subtests.
+func (tr *TestRun) CountStatus(status Status) int {
+	var count int
+	for _, pkg := range tr.pkgs {
+		count += pkg.CountStatus(status)
+	}
+	return count
+}
+
+// finish is called once the package has finished running. Tests that timed out or panicked are marked, and if
+// the package passed, tests that never reported a result (such as benchmarks) are known to have passed too.
+func (pr *PackageRun) finish(passed bool) {
+	pr.markTimedOut()
+	pr.markPanicked()
+	if !passed {
+		return
+	}
+	for _, test := range flatten(pr.Tests) {
+		test.pkgPassed = !test.finished()
+	}
+}
+
+// SkipsByReason returns the skipped tests in the run, including subtests, grouped by the reason they
+// were skipped. Tests skipped without a reason are grouped under an empty string.
+func (tr *TestRun) SkipsByReason() map[string][]*Test {
+	skips := make(map[string][]*Test)
+	for _, pkg := range tr.pkgs {
+		for _, test := range flatten(pkg.Tests) {
+			if test.Status() == StatusSkipped {
+				reason := test.SkipReason()
+				skips[reason] = append(skips[reason], test)
+			}
+		}
+	}
+	return skips
+}
@@ -0,0 +1,113 @@
+package tstat_test
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/nickfiggins/tstat"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTest_Status(t *testing.T) {
+	out := `{"Action":"start","Package":"pkg"}
+{"Action":"run","Package":"pkg","Test":"TestPass"}
+{"Action":"pass","Package":"pkg","Test":"TestPass"}
+{"Action":"run","Package":"pkg","Test":"TestFail"}
+{"Action":"fail","Package":"pkg","Test":"TestFail"}
+{"Action":"run","Package":"pkg","Test":"TestSkip"}
+{"Action":"skip","Package":"pkg","Test":"TestSkip"}
+{"Action":"run","Package":"pkg","Test":"TestTruncated"}
+{"Action":"run","Package":"pkg","Test":"TestTruncated/sub"}
+{"Action":"pass","Package":"pkg","Test":"TestTruncated/sub"}
+{"Action":"run","Package":"pkg","Test":"TestTruncated/sub2"}`
+	run, err := tstat.TestsFromReader(strings.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, _ := run.Package("pkg")
+
+	tests := map[string]tstat.Status{
+		"TestPass":      tstat.StatusPassed,
+		"TestFail":      tstat.StatusFailed,
+		"TestSkip":      tstat.StatusSkipped,
+		"TestTruncated": tstat.StatusIncomplete,
+	}
+	for name, want := range tests {
+		test, ok := pkg.Test(name)
+		if !ok {
+			t.Fatalf("test %v not found", name)
+		}
+		assert.Equal(t, want, test.Status(), "%v: got %v, want %v", name, test.Status(), want)
+	}
+
+	assert.False(t, run.Failed())
+	assert.Equal(t, 2, run.CountStatus(tstat.StatusPassed))
+	assert.Equal(t, 1, run.CountStatus(tstat.StatusFailed))
+	assert.Equal(t, 1, run.CountStatus(tstat.StatusSkipped))
+	assert.Equal(t, 2, run.CountStatus(tstat.StatusIncomplete))
+}
+
+func TestTest_Status_Fixtures(t *testing.T) {
+	tests := []struct {
+		testFile, pkg string
+		want          map[tstat.Status]int
+	}{
+		{
+			testFile: "testdata/bench/bench.json", pkg: "github.com/nickfiggins/tstat/testdata/bench",
+			want: map[tstat.Status]int{tstat.StatusPassed: 4},
+		},
+		{
+			testFile: "testdata/failures.json", pkg: "example.com/bf/timeout",
+			want: map[tstat.Status]int{tstat.StatusPassed: 1, tstat.StatusFailed: 2},
+		},
+		{
+			testFile: "testdata/bigtest.json", pkg: "github.com/nickfiggins/tstat",
+			want: map[tstat.Status]int{tstat.StatusPassed: 18},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testFile, func(t *testing.T) {
+			run, err := tstat.Tests(tt.testFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pkg, _ := run.Package(tt.pkg)
+			for _, status := range []tstat.Status{tstat.StatusPassed, tstat.StatusFailed, tstat.StatusSkipped, tstat.StatusIncomplete} {
+				assert.Equal(t, tt.want[status], pkg.CountStatus(status), "status %v", status)
+			}
+		})
+	}
+}
+
+func TestStatus_String(t *testing.T) {
+	assert.Equal(t, "passed", tstat.StatusPassed.String())
+	assert.Equal(t, "failed", tstat.StatusFailed.String())
+	assert.Equal(t, "skipped", tstat.StatusSkipped.String())
+	assert.Equal(t, "incomplete", tstat.StatusIncomplete.String())
+	assert.Equal(t, "unknown", tstat.Status(-1).String())
+}
+
+func TestTestRun_SkipsByReason(t *testing.T) {
+	run, err := tstat.Tests("testdata/skips.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string][]string)
+	for reason, tests := range run.SkipsByReason() {
+		for _, test := range tests {
+			got[reason] = append(got[reason], test.FullName)
+		}
+		sort.Strings(got[reason])
+	}
+	assert.Equal(t, map[string][]string{
+		"requires docker":                     {"TestDocker", "TestNetwork/docker"},
+		"requires network:\nno route to host": {"TestNetwork/dns"},
+		"":                                    {"TestNow"},
+	}, got)
+
+	pkg, _ := run.Package("example.com/sk")
+	test, _ := pkg.Test("TestNetwork")
+	assert.Empty(t, test.SkipReason(), "passed tests have no skip reason")
+}
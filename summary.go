@@ -0,0 +1,92 @@
+package tstat
+
+import (
+	"time"
+
+	"github.com/nickfiggins/tstat/internal/mathutil"
+)
+
+// StatusCounts is the number of tests with each status.
+type StatusCounts struct {
+	Passed     int
+	Failed     int
+	Skipped    int
+	Incomplete int
+}
+
+// Total returns the total number of tests.
+func (c StatusCounts) Total() int {
+	return c.Passed + c.Failed + c.Skipped + c.Incomplete
+}
+
+// Rate returns the percent of tests with the given status, rounded to one decimal place. If there are no
+// tests, 0 is returned.
+func (c StatusCounts) Rate(status Status) float64 {
+	var n int
+	switch status {
+	case StatusPassed:
+		n = c.Passed
+	case StatusFailed:
+		n = c.Failed
+	case StatusSkipped:
+		n = c.Skipped
+	case StatusIncomplete:
+		n = c.Incomplete
+	}
+	return mathutil.Percent(int64(n), int64(c.Total()))
+}
+
+func (c *StatusCounts) add(status Status) {
+	switch status {
+	case StatusPassed:
+		c.Passed++
+	case StatusFailed:
+		c.Failed++
+	case StatusSkipped:
+		c.Skipped++
+	case StatusIncomplete:
+		c.Incomplete++
+	}
+}
+
+// Summary summarizes the results of a test, package or test run.
+type Summary struct {
+	TopLevel StatusCounts  // TopLevel counts top-level tests, or only the test itself for a Test's summary.
+	All      StatusCounts  // All counts every test, including subtests.
+	Duration time.Duration // Duration is how long the test, package or test run took.
+	TestTime time.Duration // TestTime is the sum of the durations of the top-level tests.
+}
+
+// Summary returns the counts of the test and its subtests by status.
+func (t *Test) Summary() Summary {
+	s := Summary{Duration: t.Duration()}
+	s.addTests(t)
+	return s
+}
+
+// Summary returns the counts of the package's tests by status, and how long they took.
+func (pr *PackageRun) Summary() Summary {
+	s := Summary{Duration: pr.Duration()}
+	s.addTests(pr.Tests...)
+	return s
+}
+
+// Summary returns the counts of the tests in all packages by status, and how long they took.
+func (tr *TestRun) Summary() Summary {
+	s := Summary{Duration: tr.Duration()}
+	for _, pkg := range tr.pkgs {
+		s.addTests(pkg.Tests...)
+	}
+	return s
+}
+
+// addTests adds top-level tests, and their subtests, to the summary.
+func (s *Summary) addTests(tests ...*Test) {
+	for _, test := range tests {
+		s.TopLevel.add(test.Status())
+		s.TestTime += test.Duration()
+		for _, t := range flatten([]*Test{test}) {
+			s.All.add(t.Status())
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package tstat_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nickfiggins/tstat"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTestRun_Summary(t *testing.T) {
+	run, err := tstat.Tests("testdata/flaky.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := run.Summary()
+	assert.Equal(t, tstat.StatusCounts{Passed: 1, Failed: 2, Skipped: 1}, got.TopLevel)
+	assert.Equal(t, tstat.StatusCounts{Passed: 2, Failed: 3, Skipped: 1}, got.All)
+	assert.Equal(t, run.Duration(), got.Duration)
+	assert.Equal(t, run.Count(), got.All.Total())
+	assert.Equal(t, 50.0, got.TopLevel.Rate(tstat.StatusFailed))
+	assert.Equal(t, 33.3, got.All.Rate(tstat.StatusPassed))
+	assert.Equal(t, 0.0, got.All.Rate(tstat.StatusIncomplete))
+
+	pkg, _ := run.Package("example.com/fl")
+	assert.Equal(t, got.TopLevel, pkg.Summary().TopLevel)
+	assert.Equal(t, got.All, pkg.Summary().All)
+
+	var testTime time.Duration
+	for _, test := range pkg.Tests {
+		testTime += test.Duration()
+	}
+	assert.Equal(t, testTime, pkg.Summary().TestTime)
+
+	flaky, _ := pkg.Test("TestFlaky")
+	assert.Equal(t, tstat.Summary{
+		TopLevel: tstat.StatusCounts{Failed: 1},
+		All:      tstat.StatusCounts{Passed: 1, Failed: 2},
+		Duration: flaky.Duration(),
+		TestTime: flaky.Duration(),
+	}, flaky.Summary())
+}
+
+func TestPackageRun_Summary_Incomplete(t *testing.T) {
+	out := `{"Time":"2023-07-02T10:00:00Z","Action":"start","Package":"pkg"}
+{"Time":"2023-07-02T10:00:00Z","Action":"run","Package":"pkg","Test":"TestDone"}
+{"Time":"2023-07-02T10:00:01Z","Action":"pass","Package":"pkg","Test":"TestDone","Elapsed":1}
+{"Time":"2023-07-02T10:00:01Z","Action":"run","Package":"pkg","Test":"TestCut"}
+{"Time":"2023-07-02T10:00:01Z","Action":"run","Package":"pkg","Test":"TestCut/sub"}`
+	run, err := tstat.TestsFromReader(strings.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, _ := run.Package("pkg")
+
+	got := pkg.Summary()
+	assert.Equal(t, tstat.StatusCounts{Passed: 1, Incomplete: 1}, got.TopLevel)
+	assert.Equal(t, tstat.StatusCounts{Passed: 1, Incomplete: 2}, got.All)
+	assert.Equal(t, 66.7, got.All.Rate(tstat.StatusIncomplete))
+
+	empty := tstat.TestRun{}
+	assert.Equal(t, tstat.Summary{}, empty.Summary())
+	assert.Zero(t, empty.Summary().All.Rate(tstat.StatusPassed))
+}
@@ -0,0 +1,144 @@
+package tstat
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TAPEncoder writes test runs in the Test Anything Protocol format. Each package is written as a test
+// point, with its tests and their subtests written as nested TAP subtests.
+type TAPEncoder struct {
+	w       io.Writer
+	version int
+}
+
+// TAPOpt is a functional option for configuring a TAPEncoder.
+type TAPOpt func(*TAPEncoder)
+
+// WithTAPVersion sets the TAP version written in the header, which is 14 by default. Version 13 doesn't
+// specify subtests, but most consumers support the same indented format.
+func WithTAPVersion(version int) TAPOpt {
+	return func(e *TAPEncoder) {
+		e.version = version
+	}
+}
+
+// NewTAPEncoder returns a new TAPEncoder that writes to w.
+func NewTAPEncoder(w io.Writer, opts ...TAPOpt) *TAPEncoder {
+	e := &TAPEncoder{w: w, version: 14}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// Encode writes the test run as a TAP stream. Failed tests include a YAML diagnostic block with the
+// failure message and output, and skipped tests are marked with a SKIP directive and the skip reason.
+func (e *TAPEncoder) Encode(run TestRun) error {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "TAP version %d\n", e.version)
+	fmt.Fprintf(&b, "1..%d\n", len(run.pkgs))
+	for i, pkg := range run.pkgs {
+		writeTAPPackage(&b, i+1, pkg)
+	}
+
+	if _, err := e.w.Write(b.Bytes()); err != nil {
+		return fmt.Errorf("couldn't write tap: %w", err)
+	}
+	return nil
+}
+
+func writeTAPPackage(b *bytes.Buffer, num int, pkg PackageRun) {
+	fmt.Fprintf(b, "# Subtest: %v\n", pkg.pkgName)
+	fmt.Fprintf(b, "%v1..%d\n", tapIndent(1), len(pkg.Tests))
+	for i, test := range pkg.Tests {
+		writeTAPTest(b, 1, i+1, test)
+	}
+
+	diag := tapDiagnostic{duration: pkg.Duration()}
+	reason := pkg.FailureReason()
+	switch reason {
+	case FailureNone, FailureTests:
+	case FailureBuild:
+		diag.message = "package failed: " + reason.String()
+		diag.output = pkg.BuildOutput()
+	case FailurePanic, FailureTestMain, FailureTimeout:
+		diag.message = "package failed: " + reason.String()
+		diag.output = outputText(pkg.output)
+	}
+	writeTAPPoint(b, 0, num, !pkg.Failed(), pkg.pkgName, "", diag)
+}
+
+func writeTAPTest(b *bytes.Buffer, depth, num int, test *Test) {
+	if len(test.Subtests) > 0 {
+		fmt.Fprintf(b, "%v# Subtest: %v\n", tapIndent(depth), test.Name)
+		fmt.Fprintf(b, "%v1..%d\n", tapIndent(depth+1), len(test.Subtests))
+		for i, sub := range test.Subtests {
+			writeTAPTest(b, depth+1, i+1, sub)
+		}
+	}
+
+	diag := tapDiagnostic{duration: test.Duration()}
+	switch test.Status() {
+	case StatusPassed:
+		writeTAPPoint(b, depth, num, true, test.Name, "", diag)
+	case StatusSkipped:
+		writeTAPPoint(b, depth, num, true, test.Name, strings.TrimSpace("SKIP "+test.SkipReason()), diag)
+	case StatusFailed:
+		diag.message, diag.output = failureMessage(test), outputText(test.output)
+		writeTAPPoint(b, depth, num, false, test.Name, "", diag)
+	case StatusIncomplete:
+		diag.message, diag.output = incompleteMessage, outputText(test.output)
+		writeTAPPoint(b, depth, num, false, test.Name, "", diag)
+	}
+}
+
+// tapDiagnostic is the YAML block written after a test point.
+type tapDiagnostic struct {
+	message  string
+	duration time.Duration
+	output   string
+}
+
+// writeTAPPoint writes a test point, e.g. "not ok 1 - TestName", followed by its YAML diagnostic block.
+func writeTAPPoint(b *bytes.Buffer, depth, num int, ok bool, desc, directive string, diag tapDiagnostic) {
+	indent := tapIndent(depth)
+	result := "ok"
+	if !ok {
+		result = "not ok"
+	}
+	fmt.Fprintf(b, "%v%v %d - %v", indent, result, num, tapEscape(desc))
+	if directive != "" {
+		fmt.Fprintf(b, " # %v", tapEscape(directive))
+	}
+	b.WriteString("\n")
+
+	fmt.Fprintf(b, "%v  ---\n", indent)
+	if diag.message != "" {
+		fmt.Fprintf(b, "%v  message: %v\n", indent, strconv.Quote(diag.message))
+	}
+	fmt.Fprintf(b, "%v  duration_ms: %.3f\n", indent, float64(diag.duration)/float64(time.Millisecond))
+	if diag.output != "" {
+		// the indentation indicator is needed since the first line of output may be indented more than the
+		// rest, e.g. a continuation of a log message.
+		fmt.Fprintf(b, "%v  output: |2-\n", indent)
+		for _, line := range strings.Split(strings.TrimRight(diag.output, "\n"), "\n") {
+			fmt.Fprintf(b, "%v    %v\n", indent, sanitizeControlChars(line))
+		}
+	}
+	fmt.Fprintf(b, "%v  ...\n", indent)
+}
+
+// tapIndent returns the indentation of a subtest at the given depth, which is 4 spaces per level.
+func tapIndent(depth int) string {
+	return strings.Repeat("    ", depth)
+}
+
+// tapEscape escapes the characters that have a special meaning in a test point's description or directive.
+func tapEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "#", `\#`, "\n", " ").Replace(s)
+}
@@ -0,0 +1,143 @@
+package tstat_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/nickfiggins/tstat"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTAPEncoder_Encode(t *testing.T) {
+	out := `{"Time":"2023-07-02T10:00:00Z","Action":"start","Package":"pkg"}
+{"Time":"2023-07-02T10:00:00Z","Action":"run","Package":"pkg","Test":"TestA"}
+{"Time":"2023-07-02T10:00:00Z","Action":"output","Package":"pkg","Test":"TestA","Output":"=== RUN   TestA\n"}
+{"Time":"2023-07-02T10:00:00Z","Action":"run","Package":"pkg","Test":"TestA/one"}
+{"Time":"2023-07-02T10:00:00Z","Action":"output","Package":"pkg","Test":"TestA/one","Output":"=== RUN   TestA/one\n"}
+{"Time":"2023-07-02T10:00:00.010Z","Action":"output","Package":"pkg","Test":"TestA/one","Output":"    a_test.go:10: got 1, want 2\n"}
+{"Time":"2023-07-02T10:00:00.010Z","Action":"output","Package":"pkg","Test":"TestA/one","Output":"--- FAIL: TestA/one (0.01s)\n"}
+{"Time":"2023-07-02T10:00:00.010Z","Action":"fail","Package":"pkg","Test":"TestA/one","Elapsed":0.01}
+{"Time":"2023-07-02T10:00:00.010Z","Action":"run","Package":"pkg","Test":"TestA/#01"}
+{"Time":"2023-07-02T10:00:00.015Z","Action":"pass","Package":"pkg","Test":"TestA/#01","Elapsed":0.005}
+{"Time":"2023-07-02T10:00:00.020Z","Action":"fail","Package":"pkg","Test":"TestA","Elapsed":0.02}
+{"Time":"2023-07-02T10:00:00.020Z","Action":"run","Package":"pkg","Test":"TestB"}
+{"Time":"2023-07-02T10:00:00.020Z","Action":"output","Package":"pkg","Test":"TestB","Output":"    b_test.go:5: requires docker\n"}
+{"Time":"2023-07-02T10:00:00.020Z","Action":"skip","Package":"pkg","Test":"TestB","Elapsed":0}
+{"Time":"2023-07-02T10:00:00.030Z","Action":"fail","Package":"pkg","Elapsed":0.03}`
+	run, err := tstat.TestsFromReader(strings.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `TAP version 14
+1..1
+# Subtest: pkg
+    1..2
+    # Subtest: TestA
+        1..2
+        ok 1 - \#01
+          ---
+          duration_ms: 5.000
+          ...
+        not ok 2 - one
+          ---
+          message: "a_test.go:10: got 1, want 2"
+          duration_ms: 10.000
+          output: |2-
+            === RUN   TestA/one
+                a_test.go:10: got 1, want 2
+            --- FAIL: TestA/one (0.01s)
+          ...
+    not ok 1 - TestA
+      ---
+      message: "Failed"
+      duration_ms: 20.000
+      output: |2-
+        === RUN   TestA
+      ...
+    ok 2 - TestB # SKIP requires docker
+      ---
+      duration_ms: 0.000
+      ...
+not ok 1 - pkg
+  ---
+  duration_ms: 30.000
+  ...
+`
+	var buf bytes.Buffer
+	if err := tstat.NewTAPEncoder(&buf).Encode(run); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, want, buf.String())
+
+	buf.Reset()
+	if err := tstat.NewTAPEncoder(&buf, tstat.WithTAPVersion(13)).Encode(run); err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, strings.HasPrefix(buf.String(), "TAP version 13\n1..1\n"))
+}
+
+func TestTAPEncoder_Encode_PackageFailures(t *testing.T) {
+	run, err := tstat.Tests("testdata/failures.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := tstat.NewTAPEncoder(&buf).Encode(run); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	assert.Regexp(t, `not ok \d - example.com/bf/broken\n  ---\n  message: "package failed: build"\n`, out)
+	assert.Contains(t, out, "cannot use \"nope\"")
+	assert.Contains(t, out, "message: \"package failed: testmain\"")
+	assert.Contains(t, out, "message: \"Timed out\"")
+}
+
+func TestTAPEncoder_Encode_Attempts(t *testing.T) {
+	run, err := tstat.Tests("testdata/flaky.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := tstat.NewTAPEncoder(&buf).Encode(run); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	// a test that failed any of its -count attempts isn't ok, and its output includes every attempt.
+	assert.Contains(t, out, "        not ok 2 - sometimes\n")
+	assert.Contains(t, out, "        ok 1 - always\n")
+	assert.Equal(t, 3, strings.Count(out, "--- FAIL: TestBroken"))
+	assert.Contains(t, out, "    ok 3 - TestSkipped # SKIP not today\n")
+	assert.Contains(t, out, "    ok 4 - TestStable\n")
+}
+
+func TestTAPEncoder_Encode_IndentedOutput(t *testing.T) {
+	out := `{"Time":"2023-07-02T10:00:00Z","Action":"start","Package":"pkg"}
+{"Time":"2023-07-02T10:00:00Z","Action":"run","Package":"pkg","Test":"TestA"}
+{"Time":"2023-07-02T10:00:00Z","Action":"output","Package":"pkg","Test":"TestA","Output":"        continued from a previous line\n"}
+{"Time":"2023-07-02T10:00:00Z","Action":"output","Package":"pkg","Test":"TestA","Output":"    a_test.go:10: got 1, want 2\n"}
+{"Time":"2023-07-02T10:00:00Z","Action":"output","Package":"pkg","Test":"TestA","Output":"--- FAIL: TestA (0.00s)\n"}
+{"Time":"2023-07-02T10:00:00Z","Action":"fail","Package":"pkg","Test":"TestA","Elapsed":0}
+{"Time":"2023-07-02T10:00:00Z","Action":"fail","Package":"pkg","Elapsed":0}`
+	run, err := tstat.TestsFromReader(strings.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := tstat.NewTAPEncoder(&buf).Encode(run); err != nil {
+		t.Fatal(err)
+	}
+
+	// the YAML block of TestA, which is indented by 4 spaces since it's in the package's subtest.
+	_, block, _ := strings.Cut(buf.String(), "not ok 1 - TestA\n      ---\n")
+	block, _, _ = strings.Cut(block, "      ...\n")
+	want := `message: "a_test.go:10: got 1, want 2"
+duration_ms: 0.000
+output: "        continued from a previous line\n    a_test.go:10: got 1, want 2\n--- FAIL: TestA (0.00s)"
+`
+	assert.YAMLEq(t, want, block)
+}
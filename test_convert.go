@@ -2,7 +2,7 @@ package tstat
 
 import (
 	"fmt"
-	"sort"
+	"slices"
 	"strings"
 	"time"
 
@@ -12,10 +12,20 @@ import (
 
 const testDelim = "/"
 
-// convertTests converts a gotest.PackageEvents into a PackageRun.
+// convertEvents converts a gotest.PackageEvents into a PackageRun.
 func convertEvents(pkg *gotest.PackageEvents) (PackageRun, error) {
+	return convertPackage(pkg, false)
+}
+
+// convertEventsLenient converts a gotest.PackageEvents into a PackageRun, adding placeholders for the missing
+// parents of subtests instead of returning an error.
+func convertEventsLenient(pkg *gotest.PackageEvents) (PackageRun, error) {
+	return convertPackage(pkg, true)
+}
+
+func convertPackage(pkg *gotest.PackageEvents, lenient bool) (PackageRun, error) {
 	tests := getPackageTests(pkg.Events)
-	nested, err := nestSubtests(tests)
+	nested, warnings, err := nestSubtests(tests, lenient)
 	if err != nil {
 		return PackageRun{}, err
 	}
@@ -31,13 +41,21 @@ func convertEvents(pkg *gotest.PackageEvents) (PackageRun, error) {
 		failed = pkg.End.Action == gotest.Fail
 	}
 
-	return PackageRun{
+	run := PackageRun{
 		pkgName: pkg.Package,
 		start:   start, end: end,
-		Tests:  nested,
-		Seed:   pkg.Seed,
-		failed: failed,
-	}, nil
+		Tests:      nested,
+		Benchmarks: parseBenchmarks(pkg.Package, pkg.Events),
+		Seed:       pkg.Seed,
+		failed:     failed,
+		events:     pkg.Events,
+		warnings:   warnings,
+	}
+	for _, e := range pkg.Events {
+		run.withOutput(e)
+	}
+	run.finish(pkg.End != nil && pkg.End.Action == gotest.Pass)
+	return run, nil
 }
 
 func getPackageTests(events []gotest.Event) []*Test {
@@ -55,8 +73,9 @@ func getPackageTests(events []gotest.Event) []*Test {
 		packageTests[event.Test] = test.withEvent(event)
 	}
 
+	// sorting by name ensures that all parent tests come before their subtests.
 	testsByName := maps.Values(packageTests)
-	sort.Sort(byTestName(testsByName))
+	slices.SortFunc(testsByName, CompareTestNames)
 	return testsByName
 }
 
@@ -73,26 +92,56 @@ func newTest(pkg, name string) *Test {
 	}
 }
 
-// nestSubtests takes a list of tests and nests subtests under their parent.
-// It returns a list of root tests.
-func nestSubtests(tests []*Test) ([]*Test, error) {
+// nestSubtests takes a list of tests, sorted by name, and nests subtests under their parent.
+// It returns a list of root tests in the same order. If lenient is true, placeholders are added
+// for missing parents and a warning is returned for each subtest that was missing one.
+func nestSubtests(tests []*Test, lenient bool) ([]*Test, []ParseWarning, error) {
 	rootTests := map[string]*Test{}
+	seen := map[string]bool{}
+	roots := make([]*Test, 0)
+	var warnings []ParseWarning
 	for _, to := range tests {
-		subs := removeEmpty(strings.Split(to.FullName, testDelim))
-		subDepth := len(subs)
-		if subDepth == 1 { // root test; no subtests
-			out := to
-			rootTests[to.FullName] = out
-		} else if subDepth > 1 { // at least one subtest
-			test, ok := rootTests[subs[0]]
-			if !ok {
-				return nil, fmt.Errorf("subtest found without corresponding parent: %v", to.FullName)
+		if lenient {
+			missing, warning := missingParents(to.Package, to.FullName, func(name string) bool { return seen[name] })
+			if warning != nil {
+				warnings = append(warnings, *warning)
+			}
+			for _, parent := range missing {
+				seen[parent.FullName] = true
+				if err := nestSubtest(rootTests, &roots, parent); err != nil {
+					return nil, nil, err
+				}
 			}
-			test.addSubtests(to)
+		}
+		seen[to.FullName] = true
+		if err := nestSubtest(rootTests, &roots, to); err != nil {
+			return nil, nil, err
 		}
 	}
 
-	return maps.Values(rootTests), nil
+	if len(warnings) > 0 {
+		// placeholders are added when their first subtest is found, which may be after tests that sort after them.
+		SortTests(roots, CompareTestNames)
+		spanPlaceholders(roots)
+	}
+	return roots, warnings, nil
+}
+
+// nestSubtest adds the test to roots if it's a root test, otherwise it nests it under its root test.
+func nestSubtest(rootTests map[string]*Test, roots *[]*Test, to *Test) error {
+	subs := removeEmpty(strings.Split(to.FullName, testDelim))
+	subDepth := len(subs)
+	if subDepth == 1 { // root test; no subtests
+		rootTests[to.FullName] = to
+		*roots = append(*roots, to)
+	} else if subDepth > 1 { // at least one subtest
+		test, ok := rootTests[subs[0]]
+		if !ok {
+			return fmt.Errorf("subtest found without corresponding parent: %v", to.FullName)
+		}
+		test.addSubtests(to)
+	}
+	return nil
 }
 
 // removeEmpty removes empty strings from a slice of strings.
@@ -106,13 +155,3 @@ func removeEmpty(s []string) []string {
 	}
 	return out
 }
-
-// byTestName sorts tests by their name, which ensures that all parent tests come
-// before the subtests.
-type byTestName []*Test
-
-func (b byTestName) Len() int      { return len(b) }
-func (b byTestName) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
-func (b byTestName) Less(i, j int) bool {
-	return b[i].FullName < b[j].FullName
-}
@@ -27,7 +27,8 @@ func Test_convert(t *testing.T) {
 			want: PackageRun{
 				pkgName: "pkg",
 				Tests: []*Test{
-					{Name: "Test", Package: "pkg", FullName: "Test", actions: []gotest.Action{gotest.Run, gotest.Fail}, Subtests: []*Test{}, start: zeroPlus(1), end: zeroPlus(2)},
+					{Name: "Test", Package: "pkg", FullName: "Test", actions: []gotest.Action{gotest.Run, gotest.Fail}, Subtests: []*Test{}, start: zeroPlus(1), end: zeroPlus(2),
+						attempts: []Attempt{{Status: StatusFailed, Start: zeroPlus(1), End: zeroPlus(2), Elapsed: 123 * time.Second}}},
 				},
 			},
 		},
@@ -68,12 +69,14 @@ func Test_convert(t *testing.T) {
 								Subtests: []*Test{},
 								FullName: "Test2/sub3/sub4",
 								actions:  []gotest.Action{gotest.Pass},
+								attempts: []Attempt{{Status: StatusPassed, Start: zeroPlus(1), End: zeroPlus(1)}},
 								start:    zeroPlus(0),
 								end:      zeroPlus(1),
 							},
 						},
-						start: zeroPlus(0),
-						end:   zeroPlus(1),
+						attempts: []Attempt{{Status: StatusPassed, Start: zeroPlus(1), End: zeroPlus(1)}},
+						start:    zeroPlus(0),
+						end:      zeroPlus(1),
 					},
 				},
 			},
@@ -98,10 +101,13 @@ func Test_convert(t *testing.T) {
 						Package:  "pkg",
 						FullName: "Test2",
 						actions:  []gotest.Action{gotest.Pass},
+						attempts: []Attempt{{Status: StatusPassed}},
 						Subtests: []*Test{
 							{Name: "sub", Package: "pkg", FullName: "Test2/sub", actions: []gotest.Action{gotest.Run, gotest.Out, gotest.Pass},
+								attempts: []Attempt{{Status: StatusPassed}},
 								Subtests: []*Test{
-									{Name: "sub2", Package: "pkg", Subtests: []*Test{}, FullName: "Test2/sub/sub2", actions: []gotest.Action{gotest.Pass}},
+									{Name: "sub2", Package: "pkg", Subtests: []*Test{}, FullName: "Test2/sub/sub2", actions: []gotest.Action{gotest.Pass},
+										attempts: []Attempt{{Status: StatusPassed}}},
 								},
 							},
 						},
@@ -117,6 +123,7 @@ func Test_convert(t *testing.T) {
 				t.Errorf("convert() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			tt.want.events = tt.have.Events // the events are kept as is
 			assert.Equal(t, tt.want, got)
 		})
 	}
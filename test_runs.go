@@ -3,6 +3,8 @@ package tstat
 import (
 	"strings"
 	"time"
+
+	"github.com/nickfiggins/tstat/internal/gotest"
 )
 
 // TestRun represents the results of a test run, which may contain multiple packages.
@@ -11,7 +13,8 @@ type TestRun struct {
 	pkgs       []PackageRun
 }
 
-// Packages returns the packages that were run.
+// Packages returns the packages that were run, sorted by import path. Runs built by a TestStream list packages
+// in the order they started instead.
 func (tr *TestRun) Packages() []PackageRun {
 	return tr.pkgs
 }
@@ -28,6 +31,19 @@ func (tr *TestRun) Package(name string) (PackageRun, bool) {
 	return PackageRun{}, false
 }
 
+// addPackage adds the package run to the TestRun, widening the start and end of the run to include it.
+func (tr *TestRun) addPackage(run PackageRun) {
+	if tr.start.IsZero() || (!run.start.IsZero() && run.start.Before(tr.start)) {
+		tr.start = run.start
+	}
+
+	if tr.end.IsZero() || (!run.end.IsZero() && run.end.After(tr.end)) {
+		tr.end = run.end
+	}
+
+	tr.pkgs = append(tr.pkgs, run)
+}
+
 // Duration returns the duration of the TestRun.
 func (tr *TestRun) Duration() time.Duration {
 	return tr.end.Sub(tr.start)
@@ -55,19 +71,32 @@ func (tr *TestRun) Failed() bool {
 	return false
 }
 
-// Test is a single test, which may have subtests.
+// Test returns the test with the given name, which may be the name of a top-level test or the full name of a
+// subtest at any depth, e.g. "TestName/subtest/case". The names passed to t.Run can be used too, e.g.
+// "TestName/my case" finds "TestName/my_case", see SanitizeTestName.
 func (pr *PackageRun) Test(name string) (*Test, bool) {
 	return findTest(name, pr.Tests...)
 }
 
+// findTest returns the test with the given name or full name, trying the sanitized name if there's no match.
 func findTest(name string, tests ...*Test) (*Test, bool) {
+	if test, ok := findTestName(name, tests); ok {
+		return test, true
+	}
+	if sanitized := SanitizeTestName(name); sanitized != name {
+		return findTestName(sanitized, tests)
+	}
+	return nil, false
+}
+
+func findTestName(name string, tests []*Test) (*Test, bool) {
 	for _, test := range tests {
-		if strings.EqualFold(test.Name, name) {
+		if strings.EqualFold(test.Name, name) || strings.EqualFold(test.FullName, name) {
 			return test, true
 		}
 
-		if test.looksLikeSub(name) {
-			if sub, ok := findTest(name, test.Subtests...); ok {
+		if hasPrefixFold(name, test.FullName+testDelim) {
+			if sub, ok := findTestName(name, test.Subtests); ok {
 				return sub, true
 			}
 		}
@@ -75,14 +104,31 @@ func findTest(name string, tests ...*Test) (*Test, bool) {
 	return nil, false
 }
 
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
 // PackageRun represents the results of a package test run. If the package was run with the -shuffle flag,
 // the Seed field will be populated. Otherwise, it will be 0.
 type PackageRun struct {
 	pkgName    string
 	start, end time.Time
-	Tests      []*Test
+	Tests      []*Test     // Tests are the top-level tests, sorted by name unless read by a TestStream, see SortTests.
+	Benchmarks []Benchmark // Benchmarks are the results of any benchmarks run with the -bench flag.
 	Seed       int64
 	failed     bool
+
+	events                []gotest.Event // events are the events the package was built from, in order.
+	output                []OutputLine
+	buildOutput           []string
+	timeoutOutput         []string
+	buildFailed, panicked bool
+	warnings              []ParseWarning
+}
+
+// Name returns the import path of the package.
+func (pr *PackageRun) Name() string {
+	return pr.pkgName
 }
 
 // Duration returns the duration of the test run.
@@ -92,11 +138,7 @@ func (pr *PackageRun) Duration() time.Duration {
 
 // Count returns the total number of tests, including subtests.
 func (pr *PackageRun) Count() int {
-	var count int
-	for _, test := range pr.Tests {
-		count += test.Count()
-	}
-	return count
+	return len(flatten(pr.Tests))
 }
 
 // Failed returns true if any of the tests failed.
@@ -104,13 +146,30 @@ func (pr *PackageRun) Failed() bool {
 	return pr.failed
 }
 
+// Concurrent returns the tests in the package that were running at the same time as the given test,
+// excluding time spent paused. The test's parents and subtests aren't included. If tests were run with -count,
+// each attempt is compared separately, so tests that ran in between the attempts of the test aren't included.
+func (pr *PackageRun) Concurrent(test *Test) []*Test {
+	var concurrent []*Test
+	for _, other := range flatten(pr.Tests) {
+		if other == test || test.looksLikeSub(other.FullName) || other.looksLikeSub(test.FullName) {
+			continue
+		}
+		if test.overlaps(other) {
+			concurrent = append(concurrent, other)
+		}
+	}
+	return concurrent
+}
+
 // Failures returns the tests that failed.
 func (pr *PackageRun) Failures() []*Test {
 	var failures []*Test
-	for _, test := range pr.Tests {
-		if test.Failed() {
-			failures = append(failures, test)
+	_ = pr.Walk(func(v Visit) error {
+		if v.Test.Failed() {
+			failures = append(failures, v.Test)
 		}
-	}
+		return SkipSubtests
+	})
 	return failures
 }
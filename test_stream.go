@@ -0,0 +1,223 @@
+package tstat
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/nickfiggins/tstat/internal/gotest"
+)
+
+// TestStream incrementally builds a TestRun from test output JSON as it's read, rather than waiting
+// for the whole run to finish. Callbacks can be registered to be notified as soon as a test or package
+// finishes. A TestStream isn't safe for concurrent use.
+type TestStream struct {
+	pkgs      []*packageStream
+	byName    map[string]*packageStream
+	onTest    func(*Test)
+	onPackage func(PackageRun)
+	buf       []byte
+	lenient   bool
+	builds    map[string][]gotest.Event // builds are the events from building dependencies, by import path.
+}
+
+// StreamOpt is a functional option for configuring a TestStream.
+type StreamOpt func(*TestStream)
+
+// OnTest sets a callback that's called each time a test passes, fails or is skipped.
+func OnTest(fn func(*Test)) StreamOpt {
+	return func(s *TestStream) {
+		s.onTest = fn
+	}
+}
+
+// OnPackage sets a callback that's called each time a package passes, fails or is skipped.
+func OnPackage(fn func(PackageRun)) StreamOpt {
+	return func(s *TestStream) {
+		s.onPackage = fn
+	}
+}
+
+// NewTestStream returns a new TestStream with the given options.
+func NewTestStream(opts ...StreamOpt) *TestStream {
+	s := &TestStream{byName: make(map[string]*packageStream), builds: make(map[string][]gotest.Event)}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Consume reads test output JSON from r until EOF, processing each event as it's read.
+func (s *TestStream) Consume(r io.Reader) error {
+	return gotest.ReadEach(r, s.add)
+}
+
+// Write implements io.Writer, so the stream can be used directly as the output of `go test -json`.
+// Events are processed as soon as a full line has been written.
+func (s *TestStream) Write(p []byte) (int, error) {
+	s.buf = append(s.buf, p...)
+	for {
+		i := bytes.IndexByte(s.buf, '\n')
+		if i == -1 {
+			return len(p), nil
+		}
+		line := s.buf[:i]
+		s.buf = s.buf[i+1:]
+		if err := s.addLine(line); err != nil {
+			return len(p), err
+		}
+	}
+}
+
+// Close processes any remaining partial line written to the stream.
+func (s *TestStream) Close() error {
+	line := s.buf
+	s.buf = nil
+	return s.addLine(line)
+}
+
+// Run returns a snapshot of the TestRun built from the events read so far. Tests that haven't finished
+// yet are included. Packages and tests are in the order they started, rather than sorted by name.
+func (s *TestStream) Run() TestRun {
+	run := TestRun{}
+	for _, ps := range s.pkgs {
+		spanPlaceholders(ps.run.Tests)
+		run.addPackage(ps.run)
+	}
+	return run
+}
+
+func (s *TestStream) addLine(line []byte) error {
+	e, ok, err := gotest.ParseLine(line)
+	if err != nil || !ok {
+		return err
+	}
+	return s.add(e)
+}
+
+func (s *TestStream) add(e gotest.Event) error {
+	if e.DependencyBuild() {
+		s.builds[e.ImportPath] = append(s.builds[e.ImportPath], e)
+		return nil
+	}
+	name := e.PackageName()
+	if name == "" {
+		return nil
+	}
+
+	ps, ok := s.byName[name]
+	if !ok {
+		ps = newPackageStream(name)
+		s.byName[name] = ps
+		s.pkgs = append(s.pkgs, ps)
+	}
+	if deps := s.builds[e.FailedBuild]; e.FailedBuild != "" && len(deps) > 0 {
+		// the package failed because a dependency didn't build, so the dependency's build output belongs to it.
+		ps.run.events = append(slices.Clip(deps), ps.run.events...)
+		for _, dep := range deps {
+			ps.run.withOutput(dep)
+		}
+	}
+	ps.run.events = append(ps.run.events, e)
+	ps.run.Benchmarks = append(ps.run.Benchmarks, ps.bench.add(e)...)
+	ps.run.withOutput(e)
+
+	if e.PackageEvent() {
+		ps.withEvent(e)
+		if e.Action.IsFinal() {
+			ps.run.finish(e.Action == gotest.Pass)
+			if s.onPackage != nil {
+				s.onPackage(ps.run)
+			}
+		}
+		return nil
+	}
+
+	if len(removeEmpty(strings.Split(e.Test, testDelim))) == 0 {
+		return nil
+	}
+
+	test, err := ps.test(e.Test, s.lenient)
+	if err != nil {
+		return err
+	}
+	test.withEvent(e)
+	if e.Action.IsFinal() && s.onTest != nil {
+		s.onTest(test)
+	}
+	return nil
+}
+
+// packageStream is a package run that's still being built.
+type packageStream struct {
+	run   PackageRun
+	tests map[string]*Test
+	bench *benchmarkParser
+}
+
+func newPackageStream(pkg string) *packageStream {
+	return &packageStream{
+		run:   PackageRun{pkgName: pkg, Tests: []*Test{}},
+		tests: make(map[string]*Test),
+		bench: &benchmarkParser{pkg: pkg},
+	}
+}
+
+func (ps *packageStream) withEvent(e gotest.Event) {
+	switch {
+	case e.Action == gotest.Start:
+		if ps.run.start.IsZero() {
+			ps.run.start = e.Time
+		}
+	case e.Action.IsFinal():
+		ps.run.end = e.Time
+		ps.run.failed = e.Action == gotest.Fail
+	}
+
+	if ps.run.Seed == 0 {
+		if seed, ok := e.Seed(); ok {
+			ps.run.Seed = seed
+		}
+	}
+}
+
+// test returns the test with the given name, creating it and nesting it under its parent if it
+// hasn't been seen yet. If lenient is true, placeholders are added for any missing parents.
+func (ps *packageStream) test(name string, lenient bool) (*Test, error) {
+	if test, ok := ps.tests[name]; ok {
+		return test, nil
+	}
+
+	if lenient {
+		missing, warning := missingParents(ps.run.pkgName, name, func(name string) bool {
+			_, ok := ps.tests[name]
+			return ok
+		})
+		for _, parent := range missing {
+			if _, err := ps.test(parent.FullName, false); err != nil {
+				return nil, err
+			}
+			ps.tests[parent.FullName].placeholder = true
+		}
+		if warning != nil {
+			ps.run.warnings = append(ps.run.warnings, *warning)
+		}
+	}
+
+	test := newTest(ps.run.pkgName, name)
+	subs := removeEmpty(strings.Split(name, testDelim))
+	if len(subs) == 1 {
+		ps.run.Tests = append(ps.run.Tests, test)
+	} else {
+		root, ok := ps.tests[subs[0]]
+		if !ok {
+			return nil, fmt.Errorf("subtest found without corresponding parent: %v", name)
+		}
+		root.addSubtests(test)
+	}
+
+	ps.tests[name] = test
+	return test, nil
+}
@@ -0,0 +1,142 @@
+package tstat_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/nickfiggins/tstat"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTestStream_Consume(t *testing.T) {
+	for _, testFile := range []string{"testdata/bigtest.json", "testdata/go-cmp/go-cmp.json"} {
+		t.Run(testFile, func(t *testing.T) {
+			want, err := tstat.Tests(testFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := os.Open(testFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			var finishedTests, finishedPkgs int
+			stream := tstat.NewTestStream(
+				tstat.OnTest(func(*tstat.Test) { finishedTests++ }),
+				tstat.OnPackage(func(tstat.PackageRun) { finishedPkgs++ }),
+			)
+			if err := stream.Consume(f); err != nil {
+				t.Fatal(err)
+			}
+
+			got := stream.Run()
+			assert.Equal(t, want.Count(), got.Count())
+			assert.Equal(t, want.Failed(), got.Failed())
+			assert.Equal(t, want.Duration(), got.Duration())
+			assert.Equal(t, want.Count(), finishedTests)
+			assert.Len(t, got.Packages(), finishedPkgs)
+			for _, wantPkg := range want.Packages() {
+				if len(wantPkg.Tests) == 0 {
+					continue
+				}
+				pkg, ok := got.Package(wantPkg.Tests[0].Package)
+				if !ok {
+					t.Errorf("package %v not found", wantPkg.Tests[0].Package)
+					continue
+				}
+				assert.Equal(t, wantPkg.Count(), pkg.Count())
+				assert.Equal(t, wantPkg.Seed, pkg.Seed)
+				assert.Equal(t, wantPkg.Duration(), pkg.Duration())
+			}
+		})
+	}
+}
+
+func TestTestStream_Write(t *testing.T) {
+	var failed []string
+	stream := tstat.NewTestStream(tstat.OnTest(func(test *tstat.Test) {
+		if test.Failed() {
+			failed = append(failed, test.FullName)
+		}
+	}))
+
+	out := `{"Action":"start","Package":"pkg"}
+{"Action":"run","Package":"pkg","Test":"TestA"}
+{"Action":"run","Package":"pkg","Test":"TestA/sub"}
+{"Action":"fail","Package":"pkg","Test":"TestA/sub"}
+{"Action":"fail","Package":"pkg","Test":"TestA"}
+{"Action":"run","Package":"pkg","Test":"TestB"}`
+
+	// write in small chunks to split events across writes
+	for i := 0; i < len(out); i += 7 {
+		end := min(i+7, len(out))
+		if _, err := stream.Write([]byte(out[i:end])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assert.Equal(t, []string{"TestA/sub", "TestA"}, failed)
+
+	run := stream.Run()
+	assert.Equal(t, 2, run.Count(), "trailing line shouldn't be processed before close")
+
+	if err := stream.Close(); err != nil {
+		t.Fatal(err)
+	}
+	run = stream.Run()
+	assert.Equal(t, 3, run.Count())
+	assert.Len(t, failed, 2)
+
+	_, err := stream.Write([]byte(`{"bad": "json}` + "\n"))
+	assert.Error(t, err)
+}
+
+func TestTestStream_MissingParent(t *testing.T) {
+	stream := tstat.NewTestStream()
+	err := stream.Consume(strings.NewReader(`{"Action":"run","Package":"pkg","Test":"TestA/sub"}`))
+	assert.Error(t, err)
+}
+
+func TestTestStream_Run_Order(t *testing.T) {
+	out := `{"Time":"2023-07-02T10:00:00Z","Action":"start","Package":"pkg"}
+{"Time":"2023-07-02T10:00:00Z","Action":"run","Package":"pkg","Test":"TestB"}
+{"Time":"2023-07-02T10:00:00Z","Action":"run","Package":"pkg","Test":"TestB/z"}
+{"Time":"2023-07-02T10:00:01Z","Action":"pass","Package":"pkg","Test":"TestB/z","Elapsed":1}
+{"Time":"2023-07-02T10:00:01Z","Action":"run","Package":"pkg","Test":"TestB/a"}
+{"Time":"2023-07-02T10:00:02Z","Action":"pass","Package":"pkg","Test":"TestB/a","Elapsed":1}
+{"Time":"2023-07-02T10:00:02Z","Action":"pass","Package":"pkg","Test":"TestB","Elapsed":2}
+{"Time":"2023-07-02T10:00:02Z","Action":"run","Package":"pkg","Test":"TestA"}
+{"Time":"2023-07-02T10:00:03Z","Action":"pass","Package":"pkg","Test":"TestA","Elapsed":1}
+{"Time":"2023-07-02T10:00:03Z","Action":"pass","Package":"pkg","Elapsed":3}`
+	names := func(tests []*tstat.Test) []string {
+		var names []string
+		for _, test := range tests {
+			names = append(names, test.FullName)
+		}
+		return names
+	}
+
+	stream := tstat.NewTestStream()
+	if err := stream.Consume(strings.NewReader(out)); err != nil {
+		t.Fatal(err)
+	}
+	run := stream.Run()
+	pkg, _ := run.Package("pkg")
+	// tests and subtests are in the order they started.
+	assert.Equal(t, []string{"TestB", "TestA"}, names(pkg.Tests))
+	assert.Equal(t, []string{"TestB/z", "TestB/a"}, names(pkg.Tests[0].Subtests))
+
+	run.SortTests(tstat.CompareTestNames)
+	pkg, _ = run.Package("pkg")
+	assert.Equal(t, []string{"TestA", "TestB"}, names(pkg.Tests))
+	assert.Equal(t, []string{"TestB/a", "TestB/z"}, names(pkg.Tests[1].Subtests))
+
+	parsed, err := tstat.TestsFromReader(strings.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, _ = parsed.Package("pkg")
+	assert.Equal(t, []string{"TestB/a", "TestB/z"}, names(pkg.Tests[1].Subtests))
+}
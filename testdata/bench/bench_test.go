@@ -0,0 +1,23 @@
+package bench
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSum(t *testing.T) {
+	if got := sum([]int{1, 2, 3}); got != 6 {
+		t.Errorf("sum() = %v, want 6", got)
+	}
+}
+
+func BenchmarkSum(b *testing.B) {
+	for _, size := range []int{10, 1000} {
+		b.Run(fmt.Sprintf("size=%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = sum(make([]int, size))
+			}
+			b.ReportMetric(float64(size), "nums/op")
+		})
+	}
+}
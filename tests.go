@@ -1,6 +1,7 @@
 package tstat
 
 import (
+	"regexp"
 	"strings"
 	"time"
 
@@ -11,27 +12,78 @@ import (
 
 // Test is a single test, which may have subtests.
 type Test struct {
-	Subtests []*Test         // Subtests is a list of subtests for this test.
+	Subtests []*Test         // Subtests are sorted by name, or in the order they started for a TestStream.
 	actions  []gotest.Action // actions is a list of actions that occurred during the test.
 	FullName string          // FullName is the full name of the test, including subtests.
 	Name     string          // Name is the name of the test, without the parent test name.
 	Package  string          // Package is the package that the test belongs to.
 
+	output      []OutputLine
+	attempts    []Attempt
+	pauses      []span
+	timedOut    bool
+	panicked    bool // panicked is true if the test panicked before it reached a final action.
+	pkgPassed   bool // pkgPassed is true if the test never finished, but its package passed.
+	placeholder bool // placeholder is true if the test was only added as the parent of subtests.
+	start, end  time.Time
+}
+
+// span is a period of time, which may be open ended if end is zero.
+type span struct {
 	start, end time.Time
 }
 
+func (s span) overlaps(other span) bool {
+	return s.start.Before(other.end) && other.start.Before(s.end)
+}
+
+// OutputLine is a single line of output written during a test.
+type OutputLine struct {
+	Time time.Time // Time is when the line was written.
+	Text string    // Text is the line written, including the trailing newline if there was one.
+}
+
+// sanitizeControlChars replaces control characters other than whitespace, such as terminal escape codes, which
+// reports like JUnit XML and TAP's YAML blocks can't contain.
+func sanitizeControlChars(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\t', r == '\n', r == '\r':
+			return r
+		case r < 0x20, r == 0xFFFE, r == 0xFFFF:
+			return '�'
+		}
+		return r
+	}, s)
+}
+
 func (t *Test) withEvent(event gotest.Event) *Test {
+	if t.placeholder {
+		// the test's own events arrived after its subtests', so it's no longer a placeholder.
+		t.placeholder = false
+		t.start, t.end = time.Time{}, time.Time{}
+	}
 	t.actions = append(t.actions, event.Action)
+	t.withAttempt(event)
+	if event.Action == gotest.Out && event.Output != "" {
+		t.output = append(t.output, OutputLine{Time: event.Time, Text: event.Output})
+	}
 
 	switch event.Action { //nolint:exhaustive // no need to handle all actions
 	case gotest.Start, gotest.Run:
 		if isBefore(t.start, event.Time) {
 			t.start = event.Time
 		}
-	case gotest.Pass, gotest.Fail, gotest.Out:
+	case gotest.Pass, gotest.Fail, gotest.Out, gotest.Bench:
 		if isAfter(t.end, event.Time) {
 			t.end = event.Time
 		}
+	case gotest.Pause:
+		t.pauses = append(t.pauses, span{start: event.Time})
+	case gotest.Cont:
+		if n := len(t.pauses); n > 0 && t.pauses[n-1].end.IsZero() {
+			t.pauses[n-1].end = event.Time
+		}
 	}
 	return t
 }
@@ -45,7 +97,8 @@ func isAfter(t1, t2 time.Time) bool {
 	return t1.IsZero() || (!t2.IsZero() && t2.After(t1))
 }
 
-// Test returns the test with the given name. If the test name matches the current test, it will be returned.
+// Test returns the test with the given name, which may be the name or full name of a subtest at any depth. If
+// the test name matches the current test, it will be returned. See PackageRun.Test.
 func (t *Test) Test(name string) (*Test, bool) {
 	if name == t.FullName {
 		return t, true
@@ -54,9 +107,28 @@ func (t *Test) Test(name string) (*Test, bool) {
 	return findTest(name, t.Subtests...)
 }
 
-// Failed returns true if the test failed.
+// Failed returns true if the test failed. A test that panicked before it finished, or timed out, is considered
+// failed, as is a test that never finished because one of its subtests failed. If the test ran more than once
+// with -count, it's considered failed if any attempt failed, see Attempts and Stability for the result of each
+// attempt.
 func (t *Test) Failed() bool {
-	return slices.Contains(t.actions, gotest.Fail)
+	if slices.Contains(t.actions, gotest.Fail) {
+		return true
+	}
+	if t.panicked || t.timedOut {
+		return true
+	}
+	return !t.finished() && anyFailed(t.Subtests)
+}
+
+// TimedOut returns true if the test was still running when the test binary exceeded its -timeout.
+func (t *Test) TimedOut() bool {
+	return t.timedOut
+}
+
+// finished returns true if the test reached a final action.
+func (t *Test) finished() bool {
+	return slices.ContainsFunc(t.actions, gotest.Action.IsFinal)
 }
 
 // Skipped returns true if the test was skipped.
@@ -64,17 +136,190 @@ func (t *Test) Skipped() bool {
 	return slices.Contains(t.actions, gotest.Skip)
 }
 
+// Output returns the lines of output written during the test, in the order they were written.
+func (t *Test) Output() []OutputLine {
+	return t.output
+}
+
+// logLine matches the file and line prefix that's added to lines written with t.Log, t.Error, etc.
+var logLine = regexp.MustCompile(`^\s*\S+\.go:\d+:`) //nolint:gochecknoglobals // compiled once
+
+// FailureMessage returns the messages logged by the test before it failed, such as assertion failures
+// written by t.Errorf. Each message starts with the file and line it was written from, e.g.
+// "file_test.go:12: got 1, want 2". If the test didn't fail, an empty string is returned.
+func (t *Test) FailureMessage() string {
+	if !t.Failed() {
+		return ""
+	}
+	return strings.Join(t.logMessages(), "\n")
+}
+
+// SkipReason returns the reason passed to t.Skip, without the file and line it was written from. If the test
+// was read from a report that only has the reason's text, such as a JUnit skip message without a location, the
+// text is returned as is. If the test wasn't skipped, or was skipped without a reason, an empty string is
+// returned.
+func (t *Test) SkipReason() string {
+	if !t.Skipped() {
+		return ""
+	}
+	msgs := t.logMessages()
+	if len(msgs) == 0 {
+		return t.rawSkipMessage()
+	}
+	// t.Skip logs the reason last, since the test stops running afterwards.
+	reason := msgs[len(msgs)-1]
+	return strings.TrimSpace(reason[len(logLine.FindString(reason)):])
+}
+
+// rawSkipMessage returns the last line of output, if it wasn't written by the testing package. The testing
+// package always reports a skip with a "--- SKIP" line, so its output is never used.
+func (t *Test) rawSkipMessage() string {
+	var msg string
+	for _, line := range t.output {
+		text := strings.TrimSpace(line.Text)
+		switch {
+		case strings.HasPrefix(text, "--- SKIP"):
+			return ""
+		case text != "" && !strings.HasPrefix(text, "=== "):
+			msg = text
+		}
+	}
+	return msg
+}
+
+// logMessages returns the messages logged during the test with t.Log, t.Error, t.Skip, etc. Messages
+// that span multiple lines are joined with newlines.
+func (t *Test) logMessages() []string {
+	var msgs []string
+	indent := "" // indent is the prefix of continuation lines of the current message, if there is one.
+	for _, line := range t.output {
+		text := strings.TrimRight(line.Text, "\n")
+		switch {
+		case logLine.MatchString(text):
+			// the testing package indents messages by the test's depth, and their continuation lines by 4 more.
+			indent = text[:len(text)-len(strings.TrimLeft(text, " "))] + "    "
+			msgs = append(msgs, strings.TrimSpace(text))
+		case indent != "" && strings.HasPrefix(text, indent):
+			msgs[len(msgs)-1] += "\n" + strings.TrimPrefix(text, indent)
+		default:
+			indent = ""
+		}
+	}
+	return msgs
+}
+
+// Parallel returns true if the test was paused to run in parallel with other tests.
+func (t *Test) Parallel() bool {
+	return len(t.pauses) > 0
+}
+
+// Paused returns the total time the test spent paused, waiting to run in parallel with other tests.
+func (t *Test) Paused() time.Duration {
+	var paused time.Duration
+	for _, p := range t.pauses {
+		end := p.end
+		if end.IsZero() {
+			end = t.end
+		}
+		if end.After(p.start) {
+			paused += end.Sub(p.start)
+		}
+	}
+	return paused
+}
+
+// running returns the periods of time the test was running, excluding any time spent paused. Each attempt is
+// running separately, since other tests run in between attempts when the test is run with -count.
+func (t *Test) running() []span {
+	attempts := make([]span, 0, len(t.attempts))
+	for _, a := range t.attempts {
+		attempts = append(attempts, span{start: a.Start, end: a.End})
+	}
+	if len(attempts) == 0 {
+		// placeholders don't have attempts of their own, and span their subtests instead.
+		attempts = append(attempts, span{start: t.start, end: t.end})
+	}
+
+	var spans []span
+	for _, a := range attempts {
+		spans = append(spans, a.running(t.pauses)...)
+	}
+	return spans
+}
+
+// running returns the parts of the span that weren't paused, based on the pauses that started during it.
+func (s span) running(pauses []span) []span {
+	if s.start.IsZero() || s.end.IsZero() {
+		return nil
+	}
+
+	var spans []span
+	from := s.start
+	for _, p := range pauses {
+		if p.start.Before(s.start) || p.start.After(s.end) {
+			continue
+		}
+		spans = append(spans, span{start: from, end: p.start})
+		if p.end.IsZero() || p.end.After(s.end) {
+			return spans
+		}
+		from = p.end
+	}
+	return append(spans, span{start: from, end: s.end})
+}
+
+// overlaps returns true if both tests were running at the same time.
+func (t *Test) overlaps(other *Test) bool {
+	for _, s := range t.running() {
+		for _, o := range other.running() {
+			if s.overlaps(o) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // Count returns the total number of tests, including subtests.
 func (t *Test) Count() int {
-	count := 1
-	for _, sub := range t.Subtests {
-		count += sub.Count()
-	}
-	return count
+	return len(flatten([]*Test{t}))
 }
 
-// Duration returns the total duration of the test, including subtests.
+// Duration returns the total duration of the test, including subtests. The elapsed time reported by the
+// testing package is used if there is one, since it's measured by the test binary itself and excludes time
+// spent paused, otherwise the wall time between the test's first and last events is used.
 func (t *Test) Duration() time.Duration {
+	if elapsed, ok := t.Elapsed(); ok {
+		return elapsed
+	}
+	return t.WallTime()
+}
+
+// Elapsed returns the elapsed time reported by the testing package when the test finished. If the test ran
+// more than once with -count, the elapsed time of each attempt is added up, since attempts run one after
+// another. If the test was merged from runs that may have happened at the same time, such as shards passed
+// to MergeTestRuns, the longest of the runs is used instead. If the test didn't finish, false is returned.
+func (t *Test) Elapsed() (time.Duration, bool) {
+	if len(t.attempts) == 0 {
+		return 0, false
+	}
+	byShard := make(map[int]time.Duration)
+	for _, a := range t.attempts {
+		if a.Status == StatusIncomplete {
+			return 0, false
+		}
+		byShard[a.shard] += a.Elapsed
+	}
+	var elapsed time.Duration
+	for _, shard := range byShard {
+		elapsed = max(elapsed, shard)
+	}
+	return elapsed, true
+}
+
+// WallTime returns the time between the test's first and last events, based on their timestamps. It
+// includes time spent paused, and any delay before the test's output was written.
+func (t *Test) WallTime() time.Duration {
 	return t.end.Sub(t.start)
 }
 
@@ -2,9 +2,11 @@ package tstat
 
 import (
 	"testing"
+	"time"
 
 	"github.com/nickfiggins/tstat/internal/gotest"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestTest_addSubtests(t *testing.T) {
@@ -175,3 +177,147 @@ func TestTest_looksLikeSub(t *testing.T) {
 		})
 	}
 }
+
+func TestTest_FailureMessage(t *testing.T) {
+	lines := func(text ...string) []OutputLine {
+		out := make([]OutputLine, len(text))
+		for i, l := range text {
+			out[i] = OutputLine{Text: l}
+		}
+		return out
+	}
+	tests := []struct {
+		name    string
+		actions []gotest.Action
+		output  []OutputLine
+		want    string
+	}{
+		{
+			name:    "single error",
+			actions: []gotest.Action{gotest.Run, gotest.Out, gotest.Out, gotest.Out, gotest.Fail},
+			output: lines(
+				"=== RUN   TestAdd\n",
+				"    add_test.go:12: got 1, want 2\n",
+				"--- FAIL: TestAdd (0.00s)\n",
+			),
+			want: "add_test.go:12: got 1, want 2",
+		},
+		{
+			name:    "multi-line messages",
+			actions: []gotest.Action{gotest.Run, gotest.Fail},
+			output: lines(
+				"=== RUN   TestAdd\n",
+				"    add_test.go:12: \n",
+				"        \tError Trace:\tadd_test.go:12\n",
+				"        \tError:      \tNot equal\n",
+				"    add_test.go:13: second\n",
+				"--- FAIL: TestAdd (0.00s)\n",
+				"        not part of a message\n",
+			),
+			want: "add_test.go:12:\n\tError Trace:\tadd_test.go:12\n\tError:      \tNot equal\nadd_test.go:13: second",
+		},
+		{
+			name:    "nested subtest",
+			actions: []gotest.Action{gotest.Run, gotest.Fail},
+			output: lines(
+				"=== RUN   TestAdd/small\n",
+				"        add_test.go:12: \n",
+				"            \tError Trace:\tadd_test.go:12\n",
+				"            \tError:      \tNot equal\n",
+				"        add_test.go:13: second\n",
+				"            continued\n",
+				"    --- FAIL: TestAdd/small (0.00s)\n",
+			),
+			want: "add_test.go:12:\n\tError Trace:\tadd_test.go:12\n\tError:      \tNot equal\nadd_test.go:13: second\ncontinued",
+		},
+		{
+			name:    "passed",
+			actions: []gotest.Action{gotest.Run, gotest.Pass},
+			output:  lines("    add_test.go:12: logged\n"),
+			want:    "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test := &Test{actions: tt.actions, output: tt.output}
+			assert.Equal(t, tt.want, test.FailureMessage())
+		})
+	}
+}
+
+func TestTest_withEvent_Output(t *testing.T) {
+	test := newTest("pkg", "TestAdd")
+	test.withEvent(gotest.Event{Time: zeroPlus(1), Action: gotest.Run, Test: "TestAdd"})
+	test.withEvent(gotest.Event{Time: zeroPlus(2), Action: gotest.Out, Test: "TestAdd", Output: "=== RUN   TestAdd\n"})
+	test.withEvent(gotest.Event{Time: zeroPlus(3), Action: gotest.Out, Test: "TestAdd"})
+	test.withEvent(gotest.Event{Time: zeroPlus(4), Action: gotest.Out, Test: "TestAdd", Output: "--- PASS: TestAdd (0.00s)\n"})
+	assert.Equal(t, []OutputLine{
+		{Time: zeroPlus(2), Text: "=== RUN   TestAdd\n"},
+		{Time: zeroPlus(4), Text: "--- PASS: TestAdd (0.00s)\n"},
+	}, test.Output())
+}
+
+func TestTest_Paused(t *testing.T) {
+	events := []gotest.Event{
+		{Time: zeroPlus(1), Action: gotest.Run, Package: "pkg", Test: "TestA"},
+		{Time: zeroPlus(1), Action: gotest.Run, Package: "pkg", Test: "TestA/par1"},
+		{Time: zeroPlus(2), Action: gotest.Pause, Package: "pkg", Test: "TestA/par1"},
+		{Time: zeroPlus(2), Action: gotest.Run, Package: "pkg", Test: "TestA/par2"},
+		{Time: zeroPlus(3), Action: gotest.Pause, Package: "pkg", Test: "TestA/par2"},
+		{Time: zeroPlus(3), Action: gotest.Run, Package: "pkg", Test: "TestA/serial"},
+		{Time: zeroPlus(4), Action: gotest.Pass, Package: "pkg", Test: "TestA/serial"},
+		{Time: zeroPlus(5), Action: gotest.Cont, Package: "pkg", Test: "TestA/par1"},
+		{Time: zeroPlus(5), Action: gotest.Cont, Package: "pkg", Test: "TestA/par2"},
+		{Time: zeroPlus(6), Action: gotest.Pass, Package: "pkg", Test: "TestA/par1"},
+		{Time: zeroPlus(7), Action: gotest.Pass, Package: "pkg", Test: "TestA/par2"},
+		{Time: zeroPlus(7), Action: gotest.Pass, Package: "pkg", Test: "TestA"},
+	}
+	pkg, err := convertEvents(&gotest.PackageEvents{Package: "pkg", Events: events})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	find := func(name string) *Test {
+		t.Helper()
+		for _, test := range flatten(pkg.Tests) {
+			if test.FullName == name {
+				return test
+			}
+		}
+		t.Fatalf("test %v not found", name)
+		return nil
+	}
+	par1, par2, serial := find("TestA/par1"), find("TestA/par2"), find("TestA/serial")
+
+	assert.True(t, par1.Parallel())
+	assert.False(t, serial.Parallel())
+	assert.Equal(t, 3*time.Hour, par1.Paused())
+	assert.Equal(t, 2*time.Hour, par2.Paused())
+	assert.Equal(t, time.Duration(0), serial.Paused())
+
+	assert.Equal(t, []*Test{par2}, pkg.Concurrent(par1))
+	assert.Equal(t, []*Test{par1}, pkg.Concurrent(par2))
+	assert.Empty(t, pkg.Concurrent(serial))
+}
+
+func TestPackageRun_Concurrent_Count(t *testing.T) {
+	// run with -count=2, so TestB runs in between the attempts of TestA, but never at the same time.
+	events := []gotest.Event{
+		{Time: zeroPlus(1), Action: gotest.Run, Package: "pkg", Test: "TestA"},
+		{Time: zeroPlus(2), Action: gotest.Pass, Package: "pkg", Test: "TestA", Elapsed: 3600},
+		{Time: zeroPlus(2), Action: gotest.Run, Package: "pkg", Test: "TestB"},
+		{Time: zeroPlus(3), Action: gotest.Pass, Package: "pkg", Test: "TestB", Elapsed: 3600},
+		{Time: zeroPlus(3), Action: gotest.Run, Package: "pkg", Test: "TestA"},
+		{Time: zeroPlus(4), Action: gotest.Pass, Package: "pkg", Test: "TestA", Elapsed: 3600},
+		{Time: zeroPlus(4), Action: gotest.Run, Package: "pkg", Test: "TestB"},
+		{Time: zeroPlus(5), Action: gotest.Pass, Package: "pkg", Test: "TestB", Elapsed: 3600},
+	}
+	pkg, err := convertEvents(&gotest.PackageEvents{Package: "pkg", Events: events})
+	if err != nil {
+		t.Fatal(err)
+	}
+	require.Len(t, pkg.Tests, 2)
+
+	assert.Empty(t, pkg.Concurrent(pkg.Tests[0]))
+	assert.Empty(t, pkg.Concurrent(pkg.Tests[1]))
+}
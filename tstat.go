@@ -10,6 +10,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"slices"
 
 	"github.com/nickfiggins/tstat/internal/gocover"
 	"github.com/nickfiggins/tstat/internal/gofunc"
@@ -104,15 +105,22 @@ func (p *CoverageParser) Stats(coverProfile, fnProfile io.Reader) (Coverage, err
 // eventConverter converts a gotest.PackageEvents to a PackageRun.
 type eventConverter func(pkg *gotest.PackageEvents) (PackageRun, error)
 
-// TestParser is a parser for test output JSON.
+// TestParser is a parser for test output JSON, or other formats that describe test runs such as JUnit XML.
 type TestParser struct {
 	testParser func(io.Reader) ([]*gotest.PackageEvents, error)
 	converter  eventConverter
 }
 
-// NewTestParser returns a new TestParser.
-func NewTestParser() *TestParser {
-	return &TestParser{testParser: gotest.ReadByPackage, converter: convertEvents}
+// TestOpt is a functional option for configuring a TestParser.
+type TestOpt func(*TestParser)
+
+// NewTestParser returns a new TestParser with the given options. By default, it reads test output JSON.
+func NewTestParser(opts ...TestOpt) *TestParser {
+	tp := &TestParser{testParser: gotest.ReadByPackage, converter: convertEvents}
+	for _, opt := range opts {
+		opt(tp)
+	}
+	return tp
 }
 
 // TestsFromReader parses the test output JSON from a reader and returns a TestRun based on the output read.
@@ -146,16 +154,9 @@ func (tp *TestParser) parseTestOutputs(pkgs []*gotest.PackageEvents) (TestRun, e
 		if err != nil {
 			return TestRun{}, err
 		}
-		if suite.start.IsZero() || (!run.start.IsZero() && run.start.Before(suite.start)) {
-			suite.start = run.start
-		}
-
-		if suite.end.IsZero() || (!run.end.IsZero() && run.end.After(suite.end)) {
-			suite.end = run.end
-		}
-
-		suite.pkgs = append(suite.pkgs, run)
+		suite.addPackage(run)
 	}
+	slices.SortFunc(suite.pkgs, comparePackageNames)
 	return suite, nil
 }
 
@@ -78,9 +78,10 @@ func Test_CoverFromReaders(t *testing.T) {
 }
 
 type wantTest struct {
-	name  string
-	dur   time.Duration
-	count int
+	name     string
+	dur      time.Duration // dur is the elapsed time reported by test2json.
+	wallTime time.Duration
+	count    int
 }
 
 func Test_Tests(t *testing.T) {
@@ -147,8 +148,8 @@ func Test_Tests(t *testing.T) {
 					seed:      0,
 					testCount: 18,
 					wantTests: []wantTest{
-						{name: "Test_Internal_TestRunFromReader", dur: 353 * time.Microsecond, count: 4},
-						{name: "Test_CoverageStats", dur: 50605 * time.Microsecond, count: 3},
+						{name: "Test_Internal_TestRunFromReader", dur: 0, wallTime: 353 * time.Microsecond, count: 4},
+						{name: "Test_CoverageStats", dur: 50 * time.Millisecond, wallTime: 50605 * time.Microsecond, count: 3},
 					},
 				},
 			}},
@@ -205,4 +206,7 @@ func compareTest(t *testing.T, want wantTest, got *tstat.Test) {
 	if got.Duration() != want.dur {
 		t.Errorf("got duration %v, want %v", got.Duration(), want.dur)
 	}
+	if got.WallTime() != want.wallTime {
+		t.Errorf("got wall time %v, want %v", got.WallTime(), want.wallTime)
+	}
 }
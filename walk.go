@@ -0,0 +1,125 @@
+package tstat
+
+import (
+	"errors"
+)
+
+// SkipSubtests can be returned from a WalkFunc to skip the subtests of the test being visited. It's ignored
+// when walking in post-order, since the subtests have already been visited.
+var SkipSubtests = errors.New("skip subtests") //nolint:gochecknoglobals // sentinel error, like fs.SkipDir
+
+// SkipAll can be returned from a WalkFunc to stop walking without returning an error.
+var SkipAll = errors.New("skip all tests") //nolint:gochecknoglobals // sentinel error, like fs.SkipAll
+
+// Visit is a test being visited during a walk.
+type Visit struct {
+	Test   *Test // Test is the test being visited.
+	Parent *Test // Parent is the test's parent, or nil if it's where the walk started.
+	Depth  int   // Depth is how deeply the test is nested below where the walk started, starting at 0.
+}
+
+// WalkFunc is called for each test visited by Walk or WalkPostOrder. If it returns an error other than
+// SkipSubtests or SkipAll, the walk stops and the error is returned.
+type WalkFunc func(v Visit) error
+
+// walker visits tests, either before or after their subtests.
+type walker struct {
+	fn        WalkFunc
+	postOrder bool
+}
+
+func (w walker) walk(tests []*Test) error {
+	err := w.walkTests(tests, nil, 0)
+	if errors.Is(err, SkipAll) {
+		return nil
+	}
+	return err
+}
+
+func (w walker) walkTests(tests []*Test, parent *Test, depth int) error {
+	for _, test := range tests {
+		if err := w.walkTest(Visit{Test: test, Parent: parent, Depth: depth}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w walker) walkTest(v Visit) error {
+	if !w.postOrder {
+		if err := w.fn(v); err != nil {
+			if errors.Is(err, SkipSubtests) {
+				return nil
+			}
+			return err
+		}
+	}
+
+	if err := w.walkTests(v.Test.Subtests, v.Test, v.Depth+1); err != nil {
+		return err
+	}
+
+	if w.postOrder {
+		if err := w.fn(v); err != nil && !errors.Is(err, SkipSubtests) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Walk calls fn for the test and each of its subtests, visiting each test before its subtests. The test
+// itself is visited at depth 0 with no parent.
+func (t *Test) Walk(fn WalkFunc) error {
+	return walker{fn: fn}.walk([]*Test{t})
+}
+
+// WalkPostOrder calls fn for the test and each of its subtests, visiting each test after its subtests.
+func (t *Test) WalkPostOrder(fn WalkFunc) error {
+	return walker{fn: fn, postOrder: true}.walk([]*Test{t})
+}
+
+// Walk calls fn for each test in the package, including subtests, visiting each test before its subtests.
+// Top-level tests are visited at depth 0 with no parent.
+func (pr *PackageRun) Walk(fn WalkFunc) error {
+	return walker{fn: fn}.walk(pr.Tests)
+}
+
+// WalkPostOrder calls fn for each test in the package, including subtests, visiting each test after its
+// subtests.
+func (pr *PackageRun) WalkPostOrder(fn WalkFunc) error {
+	return walker{fn: fn, postOrder: true}.walk(pr.Tests)
+}
+
+// Walk calls fn for each test in each package, including subtests, visiting each test before its subtests.
+// Packages are walked one after another, see Test.Package for the package a test belongs to.
+func (tr *TestRun) Walk(fn WalkFunc) error {
+	return tr.walk(walker{fn: fn})
+}
+
+// WalkPostOrder calls fn for each test in each package, including subtests, visiting each test after its
+// subtests.
+func (tr *TestRun) WalkPostOrder(fn WalkFunc) error {
+	return tr.walk(walker{fn: fn, postOrder: true})
+}
+
+func (tr *TestRun) walk(w walker) error {
+	for _, pkg := range tr.pkgs {
+		if err := w.walkTests(pkg.Tests, nil, 0); err != nil {
+			if errors.Is(err, SkipAll) {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// flatten returns the tests and all of their subtests, with each test before its subtests.
+func flatten(tests []*Test) []*Test {
+	var all []*Test
+	_ = walker{fn: func(v Visit) error {
+		all = append(all, v.Test)
+		return nil
+	}}.walk(tests)
+	return all
+}
@@ -0,0 +1,118 @@
+package tstat_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/nickfiggins/tstat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func walkTree() *tstat.Test {
+	return &tstat.Test{FullName: "TestA", Name: "TestA", Subtests: []*tstat.Test{
+		{FullName: "TestA/b", Name: "b", Subtests: []*tstat.Test{
+			{FullName: "TestA/b/c", Name: "c"},
+		}},
+		{FullName: "TestA/d", Name: "d"},
+	}}
+}
+
+func TestTest_Walk(t *testing.T) {
+	visits := func(fn func(v tstat.Visit) error) []string {
+		var got []string
+		err := walkTree().Walk(func(v tstat.Visit) error {
+			parent := ""
+			if v.Parent != nil {
+				parent = v.Parent.FullName
+			}
+			got = append(got, fmt.Sprintf("%v %d %v", v.Test.FullName, v.Depth, parent))
+			return fn(v)
+		})
+		require.NoError(t, err)
+		return got
+	}
+
+	got := visits(func(tstat.Visit) error { return nil })
+	assert.Equal(t, []string{"TestA 0 ", "TestA/b 1 TestA", "TestA/b/c 2 TestA/b", "TestA/d 1 TestA"}, got)
+
+	got = visits(func(v tstat.Visit) error {
+		if v.Test.Name == "b" {
+			return tstat.SkipSubtests
+		}
+		return nil
+	})
+	assert.Equal(t, []string{"TestA 0 ", "TestA/b 1 TestA", "TestA/d 1 TestA"}, got)
+
+	got = visits(func(v tstat.Visit) error {
+		if v.Test.Name == "c" {
+			return tstat.SkipAll
+		}
+		return nil
+	})
+	assert.Equal(t, []string{"TestA 0 ", "TestA/b 1 TestA", "TestA/b/c 2 TestA/b"}, got)
+
+	errStop := errors.New("stop")
+	err := walkTree().Walk(func(v tstat.Visit) error {
+		if v.Depth == 1 {
+			return errStop
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, errStop)
+}
+
+func TestTest_WalkPostOrder(t *testing.T) {
+	var got []string
+	err := walkTree().WalkPostOrder(func(v tstat.Visit) error {
+		got = append(got, v.Test.FullName)
+		return tstat.SkipSubtests // ignored, since the subtests were already visited
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"TestA/b/c", "TestA/b", "TestA/d", "TestA"}, got)
+}
+
+func TestTestRun_Walk(t *testing.T) {
+	run, err := tstat.Tests("testdata/bigtest.json")
+	require.NoError(t, err)
+
+	var pre, post, topLevel int
+	require.NoError(t, run.Walk(func(v tstat.Visit) error {
+		pre++
+		if v.Depth == 0 {
+			assert.Nil(t, v.Parent)
+			topLevel++
+		}
+		return nil
+	}))
+	require.NoError(t, run.WalkPostOrder(func(tstat.Visit) error {
+		post++
+		return nil
+	}))
+	assert.Equal(t, run.Count(), pre)
+	assert.Equal(t, run.Count(), post)
+	assert.Equal(t, 13, topLevel)
+
+	var visited int
+	require.NoError(t, run.Walk(func(tstat.Visit) error {
+		visited++
+		if visited == 3 {
+			return tstat.SkipAll
+		}
+		return nil
+	}))
+	assert.Equal(t, 3, visited)
+
+	pkg, ok := run.Package("github.com/nickfiggins/tstat")
+	require.True(t, ok)
+	var leaves []string
+	require.NoError(t, pkg.Walk(func(v tstat.Visit) error {
+		if len(v.Test.Subtests) == 0 {
+			leaves = append(leaves, v.Test.FullName)
+		}
+		return nil
+	}))
+	assert.Contains(t, leaves, "Test_CoverageStats/happy")
+	assert.NotContains(t, leaves, "Test_CoverageStats")
+}
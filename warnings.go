@@ -0,0 +1,105 @@
+package tstat
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseWarning is a problem found in test output that was worked around instead of failing the parse, when
+// lenient parsing is enabled with WithLenientParsing or WithLenientStream.
+type ParseWarning struct {
+	Package string // Package is the package the problem was found in.
+	Test    string // Test is the full name of the test the problem was found in.
+	Message string // Message describes the problem and how it was worked around.
+}
+
+func (w ParseWarning) String() string {
+	return fmt.Sprintf("%v: %v: %v", w.Package, w.Test, w.Message)
+}
+
+// WithLenientParsing makes the TestParser keep going when a subtest's parent has no events, which happens when
+// test output is truncated or filtered. A placeholder test is added for each missing parent, and a ParseWarning
+// is recorded for the subtest, see TestRun.Warnings. By default, a subtest without a parent is an error.
+func WithLenientParsing() TestOpt {
+	return func(tp *TestParser) {
+		tp.converter = convertEventsLenient
+	}
+}
+
+// WithLenientStream makes the TestStream add placeholders for the missing parents of subtests, like
+// WithLenientParsing, instead of returning an error.
+func WithLenientStream() StreamOpt {
+	return func(s *TestStream) {
+		s.lenient = true
+	}
+}
+
+// Warnings returns the problems that were worked around while parsing the package's tests.
+func (pr *PackageRun) Warnings() []ParseWarning {
+	return pr.warnings
+}
+
+// Warnings returns the problems that were worked around while parsing the test run, for every package.
+func (tr *TestRun) Warnings() []ParseWarning {
+	var warnings []ParseWarning
+	for _, pkg := range tr.pkgs {
+		warnings = append(warnings, pkg.warnings...)
+	}
+	return warnings
+}
+
+// Placeholder returns true if the test had no events of its own, and was only added as the parent of subtests
+// that did, see WithLenientParsing. Its start and end span its subtests, and its status is derived from them:
+// it failed if any subtest failed, and passed if they all passed. Placeholders are included in counts, such as
+// Count, CountStatus and Summary, like any other test.
+func (t *Test) Placeholder() bool {
+	return t.placeholder
+}
+
+// missingParents returns placeholders for the parents of the named test that haven't been seen, starting with
+// the top-level test. It also returns a warning if there were any.
+func missingParents(pkg, name string, seen func(name string) bool) ([]*Test, *ParseWarning) {
+	subs := removeEmpty(strings.Split(name, testDelim))
+	var missing []*Test
+	for i := 1; i < len(subs); i++ {
+		parent := strings.Join(subs[:i], testDelim)
+		if seen(parent) {
+			continue
+		}
+		test := newTest(pkg, parent)
+		test.placeholder = true
+		missing = append(missing, test)
+	}
+	if len(missing) == 0 {
+		return nil, nil
+	}
+
+	names := make([]string, len(missing))
+	for i, test := range missing {
+		names[i] = test.FullName
+	}
+	return missing, &ParseWarning{
+		Package: pkg,
+		Test:    name,
+		Message: fmt.Sprintf("subtest found without corresponding parent, added placeholder for %v",
+			strings.Join(names, ", ")),
+	}
+}
+
+// spanPlaceholders sets the start and end of placeholder tests to span their subtests.
+func spanPlaceholders(tests []*Test) {
+	_ = walker{fn: func(v Visit) error {
+		if !v.Test.placeholder {
+			return nil
+		}
+		for _, sub := range flatten(v.Test.Subtests) {
+			if isBefore(v.Test.start, sub.start) {
+				v.Test.start = sub.start
+			}
+			if isAfter(v.Test.end, sub.end) {
+				v.Test.end = sub.end
+			}
+		}
+		return nil
+	}, postOrder: true}.walk(tests)
+}
@@ -0,0 +1,122 @@
+package tstat_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nickfiggins/tstat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// orphanRun is missing the events for TestGone and TestHalf/mid, as if the output was filtered.
+const orphanRun = `{"Time":"2023-07-02T10:00:00Z","Action":"start","Package":"pkg"}
+{"Time":"2023-07-02T10:00:00Z","Action":"run","Package":"pkg","Test":"TestOk"}
+{"Time":"2023-07-02T10:00:01Z","Action":"pass","Package":"pkg","Test":"TestOk","Elapsed":1}
+{"Time":"2023-07-02T10:00:01Z","Action":"run","Package":"pkg","Test":"TestGone/sub"}
+{"Time":"2023-07-02T10:00:01Z","Action":"run","Package":"pkg","Test":"TestGone/sub/leaf"}
+{"Time":"2023-07-02T10:00:03Z","Action":"fail","Package":"pkg","Test":"TestGone/sub/leaf","Elapsed":2}
+{"Time":"2023-07-02T10:00:03Z","Action":"fail","Package":"pkg","Test":"TestGone/sub","Elapsed":2}
+{"Time":"2023-07-02T10:00:03Z","Action":"run","Package":"pkg","Test":"TestHalf"}
+{"Time":"2023-07-02T10:00:03Z","Action":"run","Package":"pkg","Test":"TestHalf/mid/leaf"}
+{"Time":"2023-07-02T10:00:04Z","Action":"pass","Package":"pkg","Test":"TestHalf/mid/leaf","Elapsed":1}
+{"Time":"2023-07-02T10:00:04Z","Action":"pass","Package":"pkg","Test":"TestHalf","Elapsed":1}
+{"Time":"2023-07-02T10:00:04Z","Action":"fail","Package":"pkg","Elapsed":4}
+`
+
+func TestWithLenientParsing(t *testing.T) {
+	_, err := tstat.NewTestParser().Stats(strings.NewReader(orphanRun))
+	require.ErrorContains(t, err, "subtest found without corresponding parent")
+
+	run, err := tstat.NewTestParser(tstat.WithLenientParsing()).Stats(strings.NewReader(orphanRun))
+	require.NoError(t, err)
+	assertOrphans(t, run)
+
+	pkg, ok := run.Package("pkg")
+	require.True(t, ok)
+	names := []string{}
+	for _, test := range pkg.Tests {
+		names = append(names, test.FullName)
+	}
+	assert.Equal(t, []string{"TestGone", "TestHalf", "TestOk"}, names)
+
+	gone, ok := pkg.Test("TestGone")
+	require.True(t, ok)
+	assert.Equal(t, 2*time.Second, gone.WallTime())
+}
+
+func TestWithLenientStream(t *testing.T) {
+	err := tstat.NewTestStream().Consume(strings.NewReader(orphanRun))
+	require.ErrorContains(t, err, "subtest found without corresponding parent")
+
+	stream := tstat.NewTestStream(tstat.WithLenientStream())
+	require.NoError(t, stream.Consume(strings.NewReader(orphanRun)))
+	assertOrphans(t, stream.Run())
+}
+
+func assertOrphans(t *testing.T, run tstat.TestRun) {
+	t.Helper()
+
+	warnings := run.Warnings()
+	require.Len(t, warnings, 2)
+	assert.Equal(t, tstat.ParseWarning{
+		Package: "pkg",
+		Test:    "TestGone/sub",
+		Message: "subtest found without corresponding parent, added placeholder for TestGone",
+	}, warnings[0])
+	assert.Equal(t, "TestHalf/mid/leaf", warnings[1].Test)
+	assert.Equal(t, "pkg: TestHalf/mid/leaf: subtest found without corresponding parent, added placeholder for "+
+		"TestHalf/mid", warnings[1].String())
+
+	pkg, ok := run.Package("pkg")
+	require.True(t, ok)
+	assert.Equal(t, warnings, pkg.Warnings())
+	assert.Equal(t, 7, pkg.Count())
+
+	tests := []struct {
+		name            string
+		wantPlaceholder bool
+		wantStatus      tstat.Status
+	}{
+		{name: "TestOk", wantPlaceholder: false, wantStatus: tstat.StatusPassed},
+		{name: "TestGone", wantPlaceholder: true, wantStatus: tstat.StatusFailed},
+		{name: "TestGone/sub/leaf", wantPlaceholder: false, wantStatus: tstat.StatusFailed},
+		{name: "TestHalf", wantPlaceholder: false, wantStatus: tstat.StatusPassed},
+		{name: "TestHalf/mid", wantPlaceholder: true, wantStatus: tstat.StatusPassed},
+		{name: "TestHalf/mid/leaf", wantPlaceholder: false, wantStatus: tstat.StatusPassed},
+	}
+	for _, tt := range tests {
+		test, ok := pkg.Test(tt.name)
+		require.True(t, ok, tt.name)
+		assert.Equal(t, tt.wantPlaceholder, test.Placeholder(), tt.name)
+		assert.Equal(t, tt.wantStatus, test.Status(), tt.name)
+	}
+}
+
+func TestWithLenientStream_LateParent(t *testing.T) {
+	out := `{"Time":"2023-07-02T10:00:00Z","Action":"start","Package":"pkg"}
+{"Time":"2023-07-02T10:00:01Z","Action":"run","Package":"pkg","Test":"TestLate/sub"}
+{"Time":"2023-07-02T10:00:02Z","Action":"pass","Package":"pkg","Test":"TestLate/sub","Elapsed":1}
+`
+	stream := tstat.NewTestStream(tstat.WithLenientStream())
+	require.NoError(t, stream.Consume(strings.NewReader(out)))
+	run := stream.Run()
+	pkg, ok := run.Package("pkg")
+	require.True(t, ok)
+	parent, ok := pkg.Test("TestLate")
+	require.True(t, ok)
+	assert.True(t, parent.Placeholder())
+	assert.Equal(t, tstat.StatusPassed, parent.Status())
+	assert.Equal(t, time.Second, parent.WallTime())
+
+	late := `{"Time":"2023-07-02T10:00:00Z","Action":"run","Package":"pkg","Test":"TestLate"}
+{"Time":"2023-07-02T10:00:03Z","Action":"fail","Package":"pkg","Test":"TestLate","Elapsed":3}
+`
+	require.NoError(t, stream.Consume(strings.NewReader(late)))
+	assert.False(t, parent.Placeholder())
+	assert.Equal(t, tstat.StatusFailed, parent.Status())
+	assert.Equal(t, 3*time.Second, parent.WallTime())
+	run = stream.Run()
+	assert.Len(t, run.Warnings(), 1)
+}